@@ -0,0 +1,66 @@
+package envparser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+var stringMapType = reflect.TypeOf(map[string]string(nil))
+
+// catchAll is a map field tagged env:"*" waiting to be filled once every
+// other field has been parsed. keys lists the variables it collected.
+type catchAll struct {
+	field  reflect.Value
+	prefix string
+	secret bool
+	keys   []string
+}
+
+// consume marks the variable key as read by a field. It is safe for
+// concurrent use.
+func (p *Parser) consume(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.consumed == nil {
+		p.consumed = map[string]bool{}
+	}
+	p.consumed[key] = true
+}
+
+func (p *Parser) addCatchAll(field reflect.Value, prefix string, secret bool) error {
+	if field.Type() != stringMapType {
+		return errors.New(`env:"*" requires a map[string]string field`)
+	}
+	if err := p.checkKeyedSources(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.catchAll = append(p.catchAll, catchAll{field: field, prefix: prefix, secret: secret})
+	return nil
+}
+
+// fillCatchAll stores every variable that starts with a catch-all field's
+// prefix and was not read by another field in that field, keyed by the full
+// variable name.
+func (p *Parser) fillCatchAll() {
+	if len(p.catchAll) == 0 {
+		return
+	}
+	keys := p.environKeys()
+	for i := range p.catchAll {
+		c := &p.catchAll[i]
+		m := map[string]string{}
+		for _, key := range keys {
+			if !strings.HasPrefix(key, c.prefix) || p.consumed[key] {
+				continue
+			}
+			if val, ok, err := p.lookupKey(key); ok && err == nil {
+				m[key] = val
+				c.keys = append(c.keys, key)
+			}
+		}
+		c.field.Set(reflect.ValueOf(m))
+	}
+}
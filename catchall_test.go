@@ -0,0 +1,35 @@
+package envparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_CatchAll(t *testing.T) {
+	t.Setenv("APP_NAME", "app")
+	t.Setenv("APP_PORT", "8080")
+	t.Setenv("APP_EXTRA_ONE", "1")
+	t.Setenv("APP_EXTRA_TWO", "2")
+	t.Setenv("OTHER_VALUE", "ignored")
+	type Env struct {
+		Name  string            `env:"APP_NAME"`
+		Port  int               `env:"APP_PORT"`
+		Extra map[string]string `env:"*" prefix:"APP_"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Name, "app")
+	assert.Equal(t, env.Port, 8080)
+	assert.Equal(t, env.Extra, map[string]string{"APP_EXTRA_ONE": "1", "APP_EXTRA_TWO": "2"})
+}
+
+func TestParse_CatchAll_WrongType_Error(t *testing.T) {
+	type Env struct {
+		Extra map[string]int `env:"*" prefix:"APP_"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
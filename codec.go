@@ -0,0 +1,158 @@
+package envparser
+
+import (
+	"encoding"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// codec decodes the raw value of a field tagged with the encoding it is
+// registered under.
+type codec func(p *Parser, field reflect.Value, fieldType reflect.StructField, val string) error
+
+var codecs = map[string]codec{}
+
+func init() {
+	for name, c := range builtinCodecs() {
+		codecs[name] = c
+	}
+}
+
+// builtinCodecs returns the encodings supported out of the box. They are
+// registered from init because kv and pairs decode their parts recursively
+// through the codec registry.
+func builtinCodecs() map[string]codec {
+	return map[string]codec{
+		"json": func(p *Parser, field reflect.Value, _ reflect.StructField, val string) error {
+			if field.Kind() == reflect.Map && field.Type().Key().Kind() != reflect.String &&
+				!reflect.PtrTo(field.Type().Key()).Implements(textUnmarshalerType) {
+				return p.setJSONMap(field, val)
+			}
+			return json.Unmarshal([]byte(val), field.Addr().Interface())
+		},
+		"jsonl": func(_ *Parser, field reflect.Value, _ reflect.StructField, val string) error {
+			return setJSONLines(field, val)
+		},
+		"xml": func(_ *Parser, field reflect.Value, _ reflect.StructField, val string) error {
+			return xml.Unmarshal([]byte(val), field.Addr().Interface())
+		},
+		"form": func(_ *Parser, field reflect.Value, fieldType reflect.StructField, val string) error {
+			parsed, err := parseForm(val, fieldType.Tag.Get("formsep"))
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(parsed)) // if field is url.Values
+			return nil
+		},
+		"base64": func(_ *Parser, field reflect.Value, _ reflect.StructField, val string) error {
+			decoded, err := base64.StdEncoding.DecodeString(val)
+			if err != nil {
+				return err
+			}
+			return setBinary(field, decoded)
+		},
+		"hex": func(_ *Parser, field reflect.Value, _ reflect.StructField, val string) error {
+			decoded, err := hex.DecodeString(val)
+			if err != nil {
+				return err
+			}
+			return setBinary(field, decoded)
+		},
+		"asn1": func(_ *Parser, field reflect.Value, _ reflect.StructField, val string) error {
+			der, err := base64.StdEncoding.DecodeString(val)
+			if err != nil {
+				return err
+			}
+			rest, err := asn1.Unmarshal(der, field.Addr().Interface())
+			if err != nil {
+				return err
+			}
+			if len(rest) > 0 {
+				return fmt.Errorf("asn1: %d bytes of trailing data", len(rest))
+			}
+			return nil
+		},
+		"kv": func(p *Parser, field reflect.Value, fieldType reflect.StructField, val string) error {
+			return p.setKV(field, fieldType, val)
+		},
+		"pairs": func(p *Parser, field reflect.Value, fieldType reflect.StructField, val string) error {
+			return p.setPairs(field, fieldType, val)
+		},
+	}
+}
+
+// RegisterCodec makes encoding:"name" decode values with decode, which
+// receives the raw value and a pointer to the field. Registering an existing
+// name, including the built-in json, jsonl, xml, toml, form, base64, hex, asn1, kv
+// and pairs encodings, replaces it.
+func RegisterCodec(name string, decode func(data []byte, v interface{}) error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	codecs[name] = func(_ *Parser, field reflect.Value, _ reflect.StructField, val string) error {
+		return decode([]byte(val), field.Addr().Interface())
+	}
+}
+
+func lookupCodec(name string) (codec, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	c, ok := codecs[name]
+	return c, ok
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// setJSONMap decodes a JSON object into a map whose keys are not strings,
+// such as map[int]string, converting each object key with the same rules as
+// a field of the key type.
+func (p *Parser) setJSONMap(field reflect.Value, val string) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(val), &raw); err != nil {
+		return err
+	}
+	t := field.Type()
+	m := reflect.MakeMapWithSize(t, len(raw))
+	for k, v := range raw {
+		key := reflect.New(t.Key()).Elem()
+		if err := p.setValueFromEnv(key, reflect.StructField{Type: t.Key()}, k); err != nil {
+			return fmt.Errorf("key %q: %v", k, err)
+		}
+		elem := reflect.New(t.Elem())
+		if err := json.Unmarshal(v, elem.Interface()); err != nil {
+			return fmt.Errorf("key %q: %v", k, err)
+		}
+		m.SetMapIndex(key, elem.Elem())
+	}
+	field.Set(m)
+	return nil
+}
+
+// setJSONLines decodes JSON Lines, one JSON value per line, into the elements
+// of a slice field such as []Server. Blank lines are skipped and errors name
+// the 1-based line they occurred on.
+func setJSONLines(field reflect.Value, val string) error {
+	if field.Kind() != reflect.Slice {
+		return fmt.Errorf("jsonl encoding requires a slice field, got %s", field.Type())
+	}
+	lines := strings.Split(val, "\n")
+	list := reflect.MakeSlice(field.Type(), 0, len(lines))
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		elem := reflect.New(field.Type().Elem())
+		if err := json.Unmarshal([]byte(line), elem.Interface()); err != nil {
+			return fmt.Errorf("line %d: %v", i+1, err)
+		}
+		list = reflect.Append(list, elem.Elem())
+	}
+	field.Set(list)
+	return nil
+}
@@ -0,0 +1,140 @@
+package envparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	RegisterCodec("upper", func(data []byte, v interface{}) error {
+		s, ok := v.(*string)
+		if !ok {
+			return errors.New("upper codec requires a string")
+		}
+		*s = strings.ToUpper(string(data))
+		return nil
+	})
+}
+
+func TestParse_RegisteredCodec(t *testing.T) {
+	t.Setenv("NAME", "envparser")
+	type Env struct {
+		Name string `env:"NAME" encoding:"upper"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Name, "ENVPARSER")
+}
+
+func TestParse_RegisteredCodec_Error(t *testing.T) {
+	t.Setenv("NAME", "envparser")
+	type Env struct {
+		Name []int `env:"NAME" encoding:"upper"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_UnknownCodec_Error(t *testing.T) {
+	t.Setenv("NAME", "envparser")
+	type Env struct {
+		Name struct{ Value string } `env:"NAME" encoding:"unknown"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_Encoding_JSON_NumericKeys(t *testing.T) {
+	t.Setenv("CODES", `{"404":"not found","500":"server error"}`)
+	t.Setenv("TIMEOUTS", `{"1s":"fast","1m":"slow"}`)
+	type Env struct {
+		Codes    map[int]string           `env:"CODES" encoding:"json"`
+		Timeouts map[time.Duration]string `env:"TIMEOUTS" encoding:"json"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Codes, map[int]string{404: "not found", 500: "server error"})
+	assert.Equal(t, env.Timeouts, map[time.Duration]string{time.Second: "fast", time.Minute: "slow"})
+}
+
+func TestParse_Encoding_JSON_NumericKeys_Error(t *testing.T) {
+	t.Setenv("CODES", `{"404":"not found","teapot":"418"}`)
+	type Env struct {
+		Codes map[int]string `env:"CODES" encoding:"json"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `key "teapot"`)
+}
+
+func TestParse_Encoding_JSON_SliceOfMaps(t *testing.T) {
+	t.Setenv("RULES", `[{"a":"1"},{"b":"2","c":"3"}]`)
+	type Env struct {
+		Rules []map[string]string `env:"RULES" encoding:"json"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Rules, []map[string]string{{"a": "1"}, {"b": "2", "c": "3"}})
+}
+
+func TestParse_Encoding_JSON_SliceOfMaps_Error(t *testing.T) {
+	t.Setenv("RULES", `[{"a":"1"},{"b":2}]`)
+	type Env struct {
+		Rules []map[string]string `env:"RULES" encoding:"json"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+
+	t.Setenv("RULES", `[{"a":"1"},`)
+	err = Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "env 'RULES'")
+}
+
+func TestParse_Encoding_JSONLines(t *testing.T) {
+	t.Setenv("EVENTS", "{\"name\":\"start\",\"at\":1}\n\n{\"name\":\"stop\",\"at\":2}\n")
+	type event struct {
+		Name string `json:"name"`
+		At   int    `json:"at"`
+	}
+	type Env struct {
+		Events []event `env:"EVENTS" encoding:"jsonl"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Events, []event{{Name: "start", At: 1}, {Name: "stop", At: 2}})
+}
+
+func TestParse_Encoding_JSONLines_Error(t *testing.T) {
+	t.Setenv("EVENTS", "{\"name\":\"start\"}\n{\"name\":")
+	type event struct {
+		Name string `json:"name"`
+	}
+	type Env struct {
+		Events []event `env:"EVENTS" encoding:"jsonl"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2: unexpected end of JSON input")
+
+	type NotSlice struct {
+		Event event `env:"EVENTS" encoding:"jsonl"`
+	}
+	var notSlice NotSlice
+	err = Parse(&notSlice)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "jsonl encoding requires a slice field")
+}
@@ -0,0 +1,75 @@
+package envparser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// loadDotenv reads the dotenv files configured with WithDotenv. Later files
+// take precedence over earlier ones.
+func (p *Parser) loadDotenv() error {
+	if len(p.dotenvFiles) == 0 {
+		return nil
+	}
+
+	p.dotenv = map[string]string{}
+	for _, path := range p.dotenvFiles {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		values, err := parseDotenv(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		for k, v := range values {
+			p.dotenv[k] = v
+		}
+	}
+	return nil
+}
+
+// parseDotenv parses KEY=VALUE lines. Blank lines and lines starting with #
+// are skipped, an optional "export " prefix is allowed, double-quoted values
+// are unquoted with Go escape rules and single-quoted values are taken
+// literally.
+func parseDotenv(r io.Reader) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		i := strings.IndexByte(line, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+		key := strings.TrimSpace(line[:i])
+		val := strings.TrimSpace(line[i+1:])
+
+		switch {
+		case strings.HasPrefix(val, `"`):
+			unquoted, err := strconv.Unquote(val)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n, err)
+			}
+			val = unquoted
+		case strings.HasPrefix(val, "'") && strings.HasSuffix(val, "'") && len(val) >= 2:
+			val = val[1 : len(val)-1]
+		default:
+			if j := strings.Index(val, " #"); j >= 0 {
+				val = strings.TrimSpace(val[:j])
+			}
+		}
+		values[key] = val
+	}
+	return values, scanner.Err()
+}
@@ -0,0 +1,69 @@
+package envparser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeDotenv(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParse_WithDotenv(t *testing.T) {
+	path := writeDotenv(t, `# comment
+export APP_NAME="env app"
+PORT=8080 # inline comment
+GREETING='hello $USER'
+`)
+	type Env struct {
+		AppName  string `env:"APP_NAME"`
+		Port     int    `env:"PORT"`
+		Greeting string `env:"GREETING"`
+	}
+	var env Env
+	err := Parse(&env, WithDotenv(path))
+	assert.NoError(t, err)
+	assert.Equal(t, env.AppName, "env app")
+	assert.Equal(t, env.Port, 8080)
+	assert.Equal(t, env.Greeting, "hello $USER")
+}
+
+func TestParse_WithDotenv_Missing_Error(t *testing.T) {
+	type Env struct {
+		AppName string `env:"APP_NAME"`
+	}
+	var env Env
+	err := Parse(&env, WithDotenv(filepath.Join(t.TempDir(), ".env")))
+	assert.Error(t, err)
+}
+
+func TestParse_WithDotenv_EnvironmentWins(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	path := writeDotenv(t, "PORT=8080\n")
+	type Env struct {
+		Port int `env:"PORT"`
+	}
+	var env Env
+	err := Parse(&env, WithDotenv(path))
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 9090)
+}
+
+func TestParse_WithDotenvOverride(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	path := writeDotenv(t, "PORT=8080\n")
+	type Env struct {
+		Port int `env:"PORT"`
+	}
+	var env Env
+	err := Parse(&env, WithDotenv(path), WithDotenvOverride(true))
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 8080)
+}
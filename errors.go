@@ -0,0 +1,138 @@
+package envparser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrMissing is the error of a *FieldError for a variable that is unset and
+// has no default.
+var ErrMissing = errors.New("missing required environment variable")
+
+// FieldError describes a failure to populate the field backed by the
+// environment variable Key.
+type FieldError struct {
+	Key string
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("env '%s': %v", e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// elementError describes a list element that failed to convert, naming its
+// position and text, e.g. element 2 ("3e"): invalid syntax.
+type elementError struct {
+	index int
+	value string
+	err   error
+}
+
+func (e *elementError) Error() string {
+	err := e.err
+	if ne, ok := err.(*strconv.NumError); ok {
+		err = ne.Err
+	}
+	return fmt.Sprintf("element %d (%q): %v", e.index, e.value, err)
+}
+
+func (e *elementError) Unwrap() error {
+	return e.err
+}
+
+// ParseError aggregates the errors of every field that failed to parse.
+// Missing variables are reported together on a single line.
+type ParseError struct {
+	Errors []error
+
+	// Prefix, when set, is prepended to the header and to every error line.
+	Prefix string
+}
+
+func (e *ParseError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(e.prefixed("error parsing environment to struct:\n"))
+	var missing []string
+	for _, err := range e.Errors {
+		if fe, ok := err.(*FieldError); ok && fe.Err == ErrMissing {
+			missing = append(missing, fe.Key)
+		}
+	}
+	if len(missing) > 0 {
+		builder.WriteString(e.prefixed("missing required environment variables: " + strings.Join(missing, ", ") + "\n"))
+	}
+	for _, err := range e.Errors {
+		if fe, ok := err.(*FieldError); ok && fe.Err == ErrMissing {
+			continue
+		}
+		builder.WriteString(e.prefixed(err.Error() + "\n"))
+	}
+	return builder.String()
+}
+
+// Unwrap returns the aggregated errors, so that on Go 1.20 and later
+// errors.Is and errors.As match against each of them.
+func (e *ParseError) Unwrap() []error {
+	return e.Errors
+}
+
+func (e *ParseError) prefixed(s string) string {
+	if e.Prefix == "" {
+		return s
+	}
+	return e.Prefix + " " + s
+}
+
+// MarshalJSON renders the aggregated errors as a list of
+// {"key": ..., "error": ...} objects for tooling. Errors not tied to a
+// variable have an empty key.
+func (e *ParseError) MarshalJSON() ([]byte, error) {
+	type entry struct {
+		Key   string `json:"key"`
+		Error string `json:"error"`
+	}
+	entries := make([]entry, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		if fe, ok := err.(*FieldError); ok {
+			entries = append(entries, entry{Key: fe.Key, Error: fe.Err.Error()})
+			continue
+		}
+		entries = append(entries, entry{Error: err.Error()})
+	}
+	return json.Marshal(entries)
+}
+
+// brandError applies the prefix configured with WithErrorPrefix to err.
+func (p *Parser) brandError(err error) error {
+	if err == nil || p.errorPrefix == "" {
+		return err
+	}
+	if pe, ok := err.(*ParseError); ok {
+		pe.Prefix = p.errorPrefix
+		return pe
+	}
+	return &prefixedError{prefix: p.errorPrefix, err: err}
+}
+
+// prefixedError prepends the WithErrorPrefix prefix to an error that is not
+// a *ParseError while keeping it unwrappable.
+type prefixedError struct {
+	prefix string
+	err    error
+}
+
+func (e *prefixedError) Error() string {
+	return e.prefix + " " + e.err.Error()
+}
+
+func (e *prefixedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,54 @@
+//go:build go1.20
+// +build go1.20
+
+package envparser
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTestUnavailable = errors.New("store unavailable")
+
+func TestParseError_Is(t *testing.T) {
+	failing := SourceFunc(func(key string) (string, bool, error) {
+		if key == "TOKEN" {
+			return "", false, errTestUnavailable
+		}
+		return "8080", true, nil
+	})
+	type Env struct {
+		Port  int    `env:"PORT"`
+		Token string `env:"TOKEN"`
+	}
+	var env Env
+	err := Parse(&env, WithSource(failing))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, errTestUnavailable))
+
+	err = Parse(&env, WithSource(failing), WithErrorPrefix("[config]"))
+	assert.True(t, errors.Is(err, errTestUnavailable))
+}
+
+func TestParseError_As(t *testing.T) {
+	t.Setenv("PORT", "eighty")
+	t.Setenv("DEBUG", "true")
+	type Env struct {
+		Debug bool `env:"DEBUG"`
+		Port  int  `env:"PORT"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+
+	var fieldErr *FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, fieldErr.Key, "PORT")
+
+	var numErr *strconv.NumError
+	assert.True(t, errors.As(err, &numErr))
+	assert.Equal(t, numErr.Num, "eighty")
+}
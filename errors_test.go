@@ -0,0 +1,82 @@
+package envparser
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseError_MarshalJSON(t *testing.T) {
+	t.Setenv("PORT", "not a port")
+	t.Setenv("DEBUG", "maybe")
+	type Env struct {
+		Port  int  `env:"PORT"`
+		Debug bool `env:"DEBUG"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+
+	out, jsonErr := json.Marshal(err)
+	assert.NoError(t, jsonErr)
+
+	var entries []struct {
+		Key   string `json:"key"`
+		Error string `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(out, &entries))
+	assert.Len(t, entries, 2)
+	assert.Equal(t, entries[0].Key, "PORT")
+	assert.Contains(t, entries[0].Error, "invalid syntax")
+	assert.Equal(t, entries[1].Key, "DEBUG")
+}
+
+func TestParse_WithErrorPrefix_Missing(t *testing.T) {
+	type Env struct {
+		Port int `env:"PORT"`
+	}
+	var env Env
+	err := Parse(&env, WithErrorPrefix("[config]"))
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "[config] error parsing environment to struct:\n"+
+		"[config] missing required environment variables: PORT\n")
+}
+
+func TestParse_WithErrorPrefix_Conversion(t *testing.T) {
+	t.Setenv("PORT", "abc")
+	type Env struct {
+		Port int `env:"PORT"`
+	}
+	var env Env
+	err := Parse(&env, WithErrorPrefix("[config]"))
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "[config] error parsing environment to struct:\n"+
+		"[config] env 'PORT': strconv.Atoi: parsing \"abc\": invalid syntax\n")
+}
+
+func TestParseError_MissingAggregated(t *testing.T) {
+	t.Setenv("PORT", "abc")
+	type Env struct {
+		Host  string `env:"HOST"`
+		Port  int    `env:"PORT"`
+		User  string `env:"USER_NAME"`
+		Token string `env:"TOKEN"`
+	}
+	for _, key := range []string{"HOST", "USER_NAME", "TOKEN"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "error parsing environment to struct:\n"+
+		"missing required environment variables: HOST, USER_NAME, TOKEN\n"+
+		"env 'PORT': strconv.Atoi: parsing \"abc\": invalid syntax\n")
+
+	pe, ok := err.(*ParseError)
+	assert.True(t, ok)
+	assert.Len(t, pe.Errors, 4)
+	assert.Equal(t, pe.Errors[0].(*FieldError).Err, ErrMissing)
+}
@@ -0,0 +1,63 @@
+package envparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// keySpec is one of the variables named by an env tag such as
+// env:"NEW;OLD|legacy", together with the transform registered with
+// RegisterTransform that is applied to its value.
+type keySpec struct {
+	key       string
+	transform string
+}
+
+// parseKeySpecs splits an env tag into its ';'-separated keys, each
+// optionally followed by '|' and the name of a transform.
+func parseKeySpecs(envTag string) []keySpec {
+	parts := strings.Split(envTag, ";")
+	specs := make([]keySpec, len(parts))
+	for i, part := range parts {
+		kv := strings.SplitN(part, "|", 2)
+		specs[i].key = strings.TrimSpace(kv[0])
+		if len(kv) == 2 {
+			specs[i].transform = strings.TrimSpace(kv[1])
+		}
+	}
+	return specs
+}
+
+// lookupFirst returns the value of the first of specs whose variable is set to
+// a non-empty value, or failing that of the first one that is set at all,
+// along with the name of the variable that supplied it. The transform of the
+// key that supplied the value is applied to it.
+func (p *Parser) lookupFirst(prefix string, specs []keySpec) (string, string, bool, error) {
+	var (
+		used   keySpec
+		source = prefix + specs[0].key
+		val    string
+		ok     bool
+	)
+	for _, spec := range specs {
+		k, v, set, err := p.resolve(prefix + spec.key)
+		if err != nil {
+			return k, "", false, err
+		}
+		if set && (!ok || (val == "" && v != "")) {
+			used, source, val, ok = spec, k, v, true
+		}
+		if ok && val != "" {
+			break
+		}
+	}
+	if !ok || used.transform == "" {
+		return source, val, ok, nil
+	}
+
+	transformed, err := applyTransform(used.transform, val)
+	if err != nil {
+		return source, "", false, fmt.Errorf("%s: %v", prefix+used.key, err)
+	}
+	return source, transformed, true, nil
+}
@@ -0,0 +1,108 @@
+package envparser
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	RegisterTransform("seconds", func(val string) (string, error) {
+		if strings.ContainsAny(val, "hms") {
+			return "", errors.New("expected plain seconds")
+		}
+		return val + "s", nil
+	})
+}
+
+func TestParse_FallbackKeys(t *testing.T) {
+	type Env struct {
+		Timeout time.Duration `env:"TIMEOUT;TIMEOUT_SECS|seconds"`
+		Host    string        `env:"HOST;LEGACY_HOST"`
+	}
+	for _, tc := range []struct {
+		name string
+		vars map[string]string
+		want Env
+	}{
+		{
+			name: "new keys",
+			vars: map[string]string{"TIMEOUT": "1m30s", "TIMEOUT_SECS": "5", "HOST": "db", "LEGACY_HOST": "old-db"},
+			want: Env{Timeout: 90 * time.Second, Host: "db"},
+		},
+		{
+			name: "legacy keys",
+			vars: map[string]string{"TIMEOUT_SECS": "90", "LEGACY_HOST": "old-db"},
+			want: Env{Timeout: 90 * time.Second, Host: "old-db"},
+		},
+		{
+			name: "empty new key",
+			vars: map[string]string{"TIMEOUT": "", "TIMEOUT_SECS": "90", "HOST": "", "LEGACY_HOST": "old-db"},
+			want: Env{Timeout: 90 * time.Second, Host: "old-db"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var env Env
+			err := New(WithMapEnviron(tc.vars)).Parse(&env)
+			assert.NoError(t, err)
+			assert.Equal(t, env, tc.want)
+		})
+	}
+}
+
+func TestParse_FallbackKeys_Error(t *testing.T) {
+	type Env struct {
+		Timeout time.Duration `env:"TIMEOUT;TIMEOUT_SECS|seconds"`
+	}
+	var env Env
+	err := New(WithMapEnviron(map[string]string{"TIMEOUT_SECS": "1m"})).Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "transform seconds: expected plain seconds")
+
+	err = New(WithMapEnviron(map[string]string{})).Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required environment variables: TIMEOUT")
+
+	type Unregistered struct {
+		Timeout time.Duration `env:"TIMEOUT;TIMEOUT_SECS|unknown"`
+	}
+	var unregistered Unregistered
+	err = New(WithMapEnviron(map[string]string{"TIMEOUT_SECS": "90"})).Parse(&unregistered)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `transform "unknown" is not registered`)
+}
+
+func TestParse_FallbackKeys_Secret(t *testing.T) {
+	type Env struct {
+		Password string `env:"DB_PASSWORD;LEGACY_DB_PASSWORD" secret:"true"`
+	}
+	environ := map[string]string{"LEGACY_DB_PASSWORD": "hunter2"}
+	var env Env
+	p := New(WithMapEnviron(environ), WithUnsetSecrets())
+	raw, err := p.ParseWithRaw(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Password, "hunter2")
+	assert.Equal(t, raw, map[string]string{"LEGACY_DB_PASSWORD": "******"})
+
+	_, ok, _ := p.lookupKey("LEGACY_DB_PASSWORD")
+	assert.False(t, ok)
+}
+
+func TestParse_FallbackKeys_UnsetSecretsEnv(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "")
+	os.Unsetenv("DB_PASSWORD")
+	t.Setenv("LEGACY_DB_PASSWORD", "hunter2")
+	type Env struct {
+		Password string `env:"DB_PASSWORD;LEGACY_DB_PASSWORD" secret:"true"`
+	}
+	var env Env
+	err := Parse(&env, WithUnsetSecrets())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Password, "hunter2")
+	_, ok := os.LookupEnv("LEGACY_DB_PASSWORD")
+	assert.False(t, ok)
+}
@@ -0,0 +1,39 @@
+package envparser
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// frozen holds pointers to the structs parsed with WithFreeze until they are
+// released with Unfreeze.
+var frozen sync.Map
+
+// freeze marks the struct v as parsed, failing if it already was. It does
+// nothing without WithFreeze, so parsers that do not use it never consult the
+// shared marks.
+func (p *Parser) freeze(v reflect.Value) error {
+	if !p.freezeTargets {
+		return nil
+	}
+	if _, loaded := frozen.LoadOrStore(v.Addr().Interface(), true); !loaded {
+		return nil
+	}
+	return fmt.Errorf("target %s has already been parsed and is frozen", v.Addr().Type())
+}
+
+// unfreeze clears the mark set by freeze so that a failed parse can be
+// retried.
+func (p *Parser) unfreeze(v reflect.Value) {
+	if p.freezeTargets {
+		frozen.Delete(v.Addr().Interface())
+	}
+}
+
+// Unfreeze releases target, a pointer previously parsed with WithFreeze, so
+// that it can be parsed again and is no longer kept reachable by the freeze
+// marks.
+func Unfreeze(target interface{}) {
+	frozen.Delete(target)
+}
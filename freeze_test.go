@@ -0,0 +1,56 @@
+package envparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_WithFreeze(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	type Env struct {
+		Port int `env:"PORT"`
+	}
+	var env Env
+	err := Parse(&env, WithFreeze())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 8080)
+
+	t.Setenv("PORT", "9090")
+	err = Parse(&env, WithFreeze())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has already been parsed and is frozen")
+	assert.Equal(t, env.Port, 8080)
+
+	var other Env
+	err = Parse(&other, WithFreeze())
+	assert.NoError(t, err)
+	assert.Equal(t, other.Port, 9090)
+
+	err = Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 9090)
+
+	Unfreeze(&env)
+	Unfreeze(&other)
+	t.Setenv("PORT", "7070")
+	err = Parse(&env, WithFreeze())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 7070)
+	Unfreeze(&env)
+}
+
+func TestParse_WithFreeze_Error(t *testing.T) {
+	type Env struct {
+		Port int `env:"PORT"`
+	}
+	var env Env
+	err := Parse(&env, WithFreeze())
+	assert.Error(t, err)
+
+	t.Setenv("PORT", "8080")
+	err = Parse(&env, WithFreeze())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 8080)
+	Unfreeze(&env)
+}
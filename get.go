@@ -0,0 +1,34 @@
+//go:build go1.21
+// +build go1.21
+
+package envparser
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Get reads the environment variable named by key and converts it to T using
+// the same rules as struct fields, e.g. Get[int]("PORT") or
+// Get[[]string]("HOSTS").
+func Get[T any](key string, opts ...Option) (T, error) {
+	var result T
+	p := New(opts...)
+	if err := p.loadDotenv(); err != nil {
+		return result, p.brandError(err)
+	}
+
+	val, ok, err := p.lookup(key)
+	if err != nil {
+		return result, p.brandError(&FieldError{Key: key, Err: err})
+	}
+	if !ok {
+		return result, p.brandError(fmt.Errorf("missing %s environment", key))
+	}
+
+	field := reflect.ValueOf(&result).Elem()
+	if err := p.setValueFromEnv(field, reflect.StructField{Name: key, Type: field.Type()}, val); err != nil {
+		return result, p.brandError(&FieldError{Key: key, Err: err})
+	}
+	return result, nil
+}
@@ -0,0 +1,29 @@
+//go:build go1.21
+// +build go1.21
+
+package envparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet_Int(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	port, err := Get[int]("PORT")
+	assert.NoError(t, err)
+	assert.Equal(t, port, 8080)
+}
+
+func TestGet_StringSlice(t *testing.T) {
+	t.Setenv("HOSTS", "a,b")
+	hosts, err := Get[[]string]("HOSTS")
+	assert.NoError(t, err)
+	assert.Equal(t, hosts, []string{"a", "b"})
+}
+
+func TestGet_Missing_Error(t *testing.T) {
+	_, err := Get[int]("PORT")
+	assert.Error(t, err)
+}
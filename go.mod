@@ -2,4 +2,7 @@ module github.com/cheesycoffee/envparser
 
 go 1.12
 
-require github.com/stretchr/testify v1.10.0
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/stretchr/testify v1.10.0
+)
@@ -0,0 +1,60 @@
+package envparser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// group tracks the members of a group tag seen during a parse.
+type group struct {
+	keys       []string
+	set        []string
+	exactlyOne bool
+}
+
+// recordGroup notes that the variable key belongs to group name and whether
+// it was set. It is safe for concurrent use.
+func (p *Parser) recordGroup(name, key string, set, exactlyOne bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.groups == nil {
+		p.groups = map[string]*group{}
+	}
+	g, ok := p.groups[name]
+	if !ok {
+		g = &group{}
+		p.groups[name] = g
+	}
+	g.keys = append(g.keys, key)
+	if set {
+		g.set = append(g.set, key)
+	}
+	if exactlyOne {
+		g.exactlyOne = true
+	}
+}
+
+// checkGroups returns an error for every group whose cardinality constraint
+// is not met: by default at least one member must be set, and with
+// groupMode:"exactlyOne" on any member exactly one must be.
+func (p *Parser) checkGroups() []error {
+	names := make([]string, 0, len(p.groups))
+	for name := range p.groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		g := p.groups[name]
+		switch {
+		case len(g.set) == 0:
+			errs = append(errs, fmt.Errorf("group '%s': one of %s must be set", name, strings.Join(g.keys, ", ")))
+		case g.exactlyOne && len(g.set) > 1:
+			errs = append(errs, fmt.Errorf("group '%s': only one of %s may be set", name, strings.Join(g.set, ", ")))
+		}
+	}
+	return errs
+}
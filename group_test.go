@@ -0,0 +1,51 @@
+package envparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type atLeastOneAuth struct {
+	Token    string `env:"AUTH_TOKEN" group:"auth"`
+	Password string `env:"AUTH_PASSWORD" group:"auth"`
+}
+
+type exactlyOneAuth struct {
+	Token    string `env:"AUTH_TOKEN" group:"auth" groupMode:"exactlyOne"`
+	Password string `env:"AUTH_PASSWORD" group:"auth"`
+}
+
+func TestParse_Group_NoneSet_Error(t *testing.T) {
+	var atLeast atLeastOneAuth
+	assert.Error(t, Parse(&atLeast))
+
+	var exactly exactlyOneAuth
+	assert.Error(t, Parse(&exactly))
+}
+
+func TestParse_Group_OneSet(t *testing.T) {
+	t.Setenv("AUTH_TOKEN", "token")
+
+	var atLeast atLeastOneAuth
+	assert.NoError(t, Parse(&atLeast))
+	assert.Equal(t, atLeast.Token, "token")
+
+	var exactly exactlyOneAuth
+	assert.NoError(t, Parse(&exactly))
+	assert.Equal(t, exactly.Token, "token")
+}
+
+func TestParse_Group_MultipleSet(t *testing.T) {
+	t.Setenv("AUTH_TOKEN", "token")
+	t.Setenv("AUTH_PASSWORD", "secret")
+
+	var atLeast atLeastOneAuth
+	assert.NoError(t, Parse(&atLeast))
+	assert.Equal(t, atLeast, atLeastOneAuth{Token: "token", Password: "secret"})
+
+	var exactly exactlyOneAuth
+	err := Parse(&exactly)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "only one of AUTH_TOKEN, AUTH_PASSWORD")
+}
@@ -0,0 +1,55 @@
+package envparser
+
+import (
+	"fmt"
+	"os/user"
+	"reflect"
+	"strings"
+)
+
+// setID stores a numeric user or group ID in an integer field for
+// format:"uid" and format:"gid". Numeric values are used as is; anything else
+// is resolved as a user or group name, through os/user unless WithIDLookup
+// replaced it.
+func (p *Parser) setID(field reflect.Value, fieldType reflect.StructField, format, val string) error {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return fmt.Errorf("%s format requires an integer field, got %s", format, field.Type())
+	}
+
+	id := strings.TrimSpace(val)
+	if id == "" || !isDigits(id) {
+		var err error
+		if id, err = p.resolveID(format, id); err != nil {
+			return err
+		}
+	}
+	return p.setValueFromEnv(field, reflect.StructField{Name: fieldType.Name, Type: fieldType.Type}, id)
+}
+
+// resolveID returns the numeric ID of the user or group called name.
+func (p *Parser) resolveID(format, name string) (string, error) {
+	if format == "uid" {
+		lookup := p.lookupUser
+		if lookup == nil {
+			lookup = user.Lookup
+		}
+		u, err := lookup(name)
+		if err != nil {
+			return "", err
+		}
+		return u.Uid, nil
+	}
+
+	lookup := p.lookupGroup
+	if lookup == nil {
+		lookup = user.LookupGroup
+	}
+	g, err := lookup(name)
+	if err != nil {
+		return "", err
+	}
+	return g.Gid, nil
+}
@@ -0,0 +1,64 @@
+package envparser
+
+import (
+	"fmt"
+	"os/user"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func stubUsers(name string) (*user.User, error) {
+	if name == "appuser" {
+		return &user.User{Username: name, Uid: "1001", Gid: "1001"}, nil
+	}
+	return nil, user.UnknownUserError(name)
+}
+
+func stubGroups(name string) (*user.Group, error) {
+	if name == "app" {
+		return &user.Group{Name: name, Gid: "2001"}, nil
+	}
+	return nil, fmt.Errorf("group: unknown group %s", name)
+}
+
+func TestParse_UID(t *testing.T) {
+	t.Setenv("RUN_AS", "1000")
+	t.Setenv("RUN_GROUP", "100")
+	type Env struct {
+		RunAs    int    `env:"RUN_AS" format:"uid"`
+		RunGroup uint32 `env:"RUN_GROUP" format:"gid"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.RunAs, 1000)
+	assert.Equal(t, env.RunGroup, uint32(100))
+
+	t.Setenv("RUN_AS", "appuser")
+	t.Setenv("RUN_GROUP", "app")
+	err = Parse(&env, WithIDLookup(stubUsers, stubGroups))
+	assert.NoError(t, err)
+	assert.Equal(t, env.RunAs, 1001)
+	assert.Equal(t, env.RunGroup, uint32(2001))
+}
+
+func TestParse_UID_Error(t *testing.T) {
+	t.Setenv("RUN_AS", "nobody-here")
+	type Env struct {
+		RunAs int `env:"RUN_AS" format:"uid"`
+	}
+	var env Env
+	err := Parse(&env, WithIDLookup(stubUsers, stubGroups))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown user nobody-here")
+
+	t.Setenv("RUN_AS", "appuser")
+	type StringEnv struct {
+		RunAs string `env:"RUN_AS" format:"uid"`
+	}
+	var stringEnv StringEnv
+	err = Parse(&stringEnv, WithIDLookup(stubUsers, stubGroups))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "uid format requires an integer field")
+}
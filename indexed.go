@@ -0,0 +1,130 @@
+package envparser
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// parseIndexed populates a slice field tagged indexed:"true" from numbered
+// variables: KEY_0, KEY_1, ... for scalar elements and KEY_0_FIELD,
+// KEY_1_FIELD, ... for struct elements. Parsing stops at the first missing
+// index unless sparse slices are enabled, in which case gaps up to the
+// highest index are left as zero values. It returns the number of elements
+// read, zero meaning no indexed variables were found, and the names of the
+// variables that supplied scalar elements.
+func (p *Parser) parseIndexed(field reflect.Value, fieldType reflect.StructField, key string, depth int) (int, []string, error) {
+	elemType := field.Type().Elem()
+	isStruct := elemType.Kind() == reflect.Struct && !isScalarStruct(elemType)
+
+	if err := p.checkKeyedSources(); err != nil {
+		return 0, nil, &FieldError{Key: key, Err: err}
+	}
+	present := p.indices(key+"_", isStruct)
+	n := 0
+	if p.sparseSlices {
+		for i := range present {
+			if i+1 > n {
+				n = i + 1
+			}
+		}
+	} else {
+		for present[n] {
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, nil, nil
+	}
+
+	var read []string
+	slice := reflect.MakeSlice(field.Type(), n, n)
+	for i := 0; i < n; i++ {
+		if !present[i] {
+			continue
+		}
+		elemKey := key + "_" + strconv.Itoa(i)
+		if isStruct {
+			if err := p.parseStruct(slice.Index(i), depth+1, elemKey+"_"); err != nil {
+				return 0, nil, err
+			}
+			continue
+		}
+		source, val, _, err := p.resolve(elemKey)
+		if err != nil {
+			return 0, nil, &FieldError{Key: elemKey, Err: err}
+		}
+		read = append(read, source)
+		if err := p.setValueFromEnv(slice.Index(i), fieldType, val); err != nil {
+			return 0, nil, &FieldError{Key: elemKey, Err: err}
+		}
+	}
+	field.Set(slice)
+	return n, read, nil
+}
+
+// indices returns the set of indexes i for which a variable named prefix+i
+// exists, or prefix+i+"_..." when nested is true.
+func (p *Parser) indices(prefix string, nested bool) map[int]bool {
+	present := map[int]bool{}
+	for _, name := range p.environKeys() {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rest := name[len(prefix):]
+		end := 0
+		for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			continue
+		}
+		if nested && !strings.HasPrefix(rest[end:], "_") || !nested && rest[end:] != "" {
+			continue
+		}
+		if i, err := strconv.Atoi(rest[:end]); err == nil {
+			present[i] = true
+		}
+	}
+	return present
+}
+
+// environKeys returns the names of all variables visible to the parser,
+// sorted and without duplicates.
+func (p *Parser) environKeys() []string {
+	seen := map[string]bool{}
+	for _, s := range p.activeSources() {
+		if keyed, ok := s.(KeyedSource); ok {
+			for _, k := range keyed.Keys() {
+				seen[k] = true
+			}
+		}
+	}
+	for k := range p.dotenv {
+		seen[k] = true
+	}
+
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isScalarStruct reports whether values of the struct type t are parsed from
+// a single variable rather than field by field, either by a built-in
+// conversion or through one of the interfaces the parser consults.
+func isScalarStruct(t reflect.Type) bool {
+	if t == timeType {
+		return true
+	}
+	for _, iface := range []reflect.Type{envUnmarshalerType, textUnmarshalerType, scannerType} {
+		if reflect.PtrTo(t).Implements(iface) {
+			return true
+		}
+	}
+	_, ok := typeParsers[t]
+	return ok
+}
@@ -0,0 +1,79 @@
+package envparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_Indexed_StopsAtGap(t *testing.T) {
+	t.Setenv("ITEM_0", "a")
+	t.Setenv("ITEM_1", "b")
+	t.Setenv("ITEM_3", "d")
+	type Env struct {
+		Items []string `env:"ITEM" indexed:"true"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Items, []string{"a", "b"})
+}
+
+func TestParse_Indexed_Scalars(t *testing.T) {
+	t.Setenv("TAG_0", "a,b")
+	t.Setenv("TAG_1", "c")
+	t.Setenv("PORT_0", "80")
+	t.Setenv("PORT_1", "443")
+	t.Setenv("PORT_3", "8080")
+	type Env struct {
+		Tags  []string `env:"TAG" indexed:"true"`
+		Ports []int    `env:"PORT" indexed:"true"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Tags, []string{"a,b", "c"})
+	assert.Equal(t, env.Ports, []int{80, 443})
+}
+
+func TestParse_Indexed_Scalars_Error(t *testing.T) {
+	t.Setenv("PORT_0", "80")
+	t.Setenv("PORT_1", "https")
+	type Env struct {
+		Ports []int `env:"PORT" indexed:"true"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "env 'PORT_1'")
+}
+
+func TestParse_Indexed_WithSparseSlices(t *testing.T) {
+	t.Setenv("ITEM_0", "a")
+	t.Setenv("ITEM_2", "c")
+	type Env struct {
+		Items []string `env:"ITEM" indexed:"true"`
+	}
+	var env Env
+	err := Parse(&env, WithSparseSlices())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Items, []string{"a", "", "c"})
+}
+
+func TestParse_Indexed_Structs(t *testing.T) {
+	t.Setenv("SERVER_0_HOST", "a")
+	t.Setenv("SERVER_0_PORT", "80")
+	t.Setenv("SERVER_2_HOST", "c")
+	t.Setenv("SERVER_2_PORT", "82")
+	type Server struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+	}
+	type Env struct {
+		Servers []Server `env:"SERVER" indexed:"true"`
+	}
+	var env Env
+	err := Parse(&env, WithSparseSlices())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Servers, []Server{{Host: "a", Port: 80}, {}, {Host: "c", Port: 82}})
+}
@@ -0,0 +1,39 @@
+package envparser
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+var fieldRefPattern = regexp.MustCompile(`\$\{field:([^}]*)\}`)
+
+// interpolateFields replaces ${field:Name} references in val with the parsed
+// values of the fields named Name that are declared before field i of the
+// struct v. Fields are parsed in declaration order, so later fields are not
+// available yet.
+func (p *Parser) interpolateFields(v reflect.Value, i, depth int, val string) (string, error) {
+	if !fieldRefPattern.MatchString(val) {
+		return val, nil
+	}
+	if depth == 0 && p.concurrency > 1 {
+		return "", fmt.Errorf("${field:...} references are not supported with WithConcurrency")
+	}
+
+	var err error
+	result := fieldRefPattern.ReplaceAllStringFunc(val, func(ref string) string {
+		name := fieldRefPattern.FindStringSubmatch(ref)[1]
+		sf, ok := v.Type().FieldByName(name)
+		switch {
+		case !ok || len(sf.Index) != 1:
+			err = fmt.Errorf("unknown field %q in %s", name, ref)
+		case sf.Index[0] >= i:
+			err = fmt.Errorf("field %q in %s must be declared before %s", name, ref, v.Type().Field(i).Name)
+		}
+		if err != nil {
+			return ref
+		}
+		return formatValue(v.Field(sf.Index[0]), sf.Tag)
+	})
+	return result, err
+}
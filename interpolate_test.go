@@ -0,0 +1,44 @@
+package envparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_FieldInterpolation(t *testing.T) {
+	t.Setenv("HOST", "db.internal")
+	t.Setenv("URL", "postgres://${field:Host}:${field:Port}/app")
+	type Env struct {
+		Host    string `env:"HOST"`
+		Port    int    `env:"PORT" default:"5432"`
+		URL     string `env:"URL"`
+		Metrics string `env:"METRICS_URL" default:"http://${field:Host}:9090"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.URL, "postgres://db.internal:5432/app")
+	assert.Equal(t, env.Metrics, "http://db.internal:9090")
+}
+
+func TestParse_FieldInterpolation_Error(t *testing.T) {
+	t.Setenv("URL", "postgres://${field:Host}/app")
+	t.Setenv("HOST", "db.internal")
+	type Env struct {
+		URL  string `env:"URL"`
+		Host string `env:"HOST"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `field "Host" in ${field:Host} must be declared before URL`)
+
+	type Unknown struct {
+		URL string `env:"URL"`
+	}
+	var unknown Unknown
+	err = Parse(&unknown)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown field "Host"`)
+}
@@ -0,0 +1,18 @@
+//go:build go1.18
+// +build go1.18
+
+package envparser
+
+import (
+	"net/netip"
+	"reflect"
+)
+
+func init() {
+	typeParsers[reflect.TypeOf(netip.Addr{})] = func(val string) (interface{}, error) {
+		return netip.ParseAddr(val)
+	}
+	typeParsers[reflect.TypeOf(netip.Prefix{})] = func(val string) (interface{}, error) {
+		return netip.ParsePrefix(val)
+	}
+}
@@ -0,0 +1,59 @@
+//go:build go1.18
+// +build go1.18
+
+package envparser
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_NetipAddr(t *testing.T) {
+	t.Setenv("ADDR", "192.168.1.10")
+	t.Setenv("ADDRS", "10.0.0.1, ::1")
+	type Env struct {
+		Addr  netip.Addr   `env:"ADDR"`
+		Addrs []netip.Addr `env:"ADDRS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Addr, netip.MustParseAddr("192.168.1.10"))
+	assert.Equal(t, env.Addrs, []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1")})
+}
+
+func TestParse_NetipAddr_Error(t *testing.T) {
+	t.Setenv("ADDR", "192.168.1.300")
+	type Env struct {
+		Addr netip.Addr `env:"ADDR"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_NetipPrefix(t *testing.T) {
+	t.Setenv("PREFIX", "10.0.0.0/8")
+	t.Setenv("PREFIXES", "10.0.0.0/8,fd00::/8")
+	type Env struct {
+		Prefix   netip.Prefix   `env:"PREFIX"`
+		Prefixes []netip.Prefix `env:"PREFIXES"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Prefix, netip.MustParsePrefix("10.0.0.0/8"))
+	assert.Equal(t, env.Prefixes, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("fd00::/8")})
+}
+
+func TestParse_NetipPrefix_Error(t *testing.T) {
+	t.Setenv("PREFIXES", "10.0.0.0/8,10.0.0.0/33")
+	type Env struct {
+		Prefixes []netip.Prefix `env:"PREFIXES"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
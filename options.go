@@ -0,0 +1,267 @@
+package envparser
+
+import (
+	"os/user"
+	"time"
+)
+
+// Option configures a Parser.
+type Option func(*Parser)
+
+// WithKeyNormalization makes lookups fall back to the "_", "-" and "."
+// separated variants of a key, so that env:"APP_PORT" also matches variables
+// named app.port or app-port.
+func WithKeyNormalization() Option {
+	return func(p *Parser) {
+		p.normalizeKeys = true
+	}
+}
+
+// WithRequireTags makes Parse fail for every exported, non-struct field that
+// has no env tag. Fields can still be skipped explicitly with env:"-".
+func WithRequireTags() Option {
+	return func(p *Parser) {
+		p.requireTags = true
+	}
+}
+
+// WithNilEmpty leaves slice and map fields nil when their variable is set to
+// an empty value. By default an empty value yields an empty, non-nil slice.
+func WithNilEmpty() Option {
+	return func(p *Parser) {
+		p.nilEmpty = true
+	}
+}
+
+// WithMaxDepth limits how many levels of nested structs below the target are
+// parsed. Deeper structs make Parse return an error. A limit of zero or less
+// means no limit.
+func WithMaxDepth(n int) Option {
+	return func(p *Parser) {
+		p.maxDepth = n
+	}
+}
+
+// WithDotenv adds the given dotenv files as a source of values. They are read
+// on every call to Parse; values in later files take precedence over earlier
+// ones. By default the process environment wins over dotenv values, see
+// WithDotenvOverride.
+func WithDotenv(paths ...string) Option {
+	return func(p *Parser) {
+		p.dotenvFiles = append(p.dotenvFiles, paths...)
+	}
+}
+
+// WithDotenvOverride controls whether values from dotenv files override
+// variables set in the process environment. It defaults to false.
+func WithDotenvOverride(override bool) Option {
+	return func(p *Parser) {
+		p.dotenvOverride = override
+	}
+}
+
+// WithDefaultFunc computes the default for the variable key at parse time.
+// fn is only called when key is unset and its field has no default tag; an
+// error from fn fails that field.
+func WithDefaultFunc(key string, fn func() (string, error)) Option {
+	return func(p *Parser) {
+		if p.defaultFuncs == nil {
+			p.defaultFuncs = map[string]func() (string, error){}
+		}
+		p.defaultFuncs[key] = fn
+	}
+}
+
+// WithSparseSlices lets slices tagged indexed:"true" contain gaps: instead of
+// stopping at the first missing index, elements up to the highest index found
+// are read and missing ones are left as zero values.
+func WithSparseSlices() Option {
+	return func(p *Parser) {
+		p.sparseSlices = true
+	}
+}
+
+// WithErrorPrefix prepends prefix, e.g. "[config]", to every error message
+// returned by Parse, including each line of an aggregated error.
+func WithErrorPrefix(prefix string) Option {
+	return func(p *Parser) {
+		p.errorPrefix = prefix
+	}
+}
+
+// WithIgnoreUnknownKV skips keys without a matching field when decoding
+// encoding:"kv" values instead of failing.
+func WithIgnoreUnknownKV() Option {
+	return func(p *Parser) {
+		p.ignoreUnknownKV = true
+	}
+}
+
+// WithNow sets the function returning the reference time for
+// format:"relative" values. It defaults to time.Now.
+func WithNow(now func() time.Time) Option {
+	return func(p *Parser) {
+		p.now = now
+	}
+}
+
+// WithIDLookup replaces the os/user functions that resolve user and group
+// names for format:"uid" and format:"gid" fields, e.g. with stubs in tests or
+// a directory service. A nil function keeps the os/user default.
+func WithIDLookup(users func(name string) (*user.User, error), groups func(name string) (*user.Group, error)) Option {
+	return func(p *Parser) {
+		p.lookupUser = users
+		p.lookupGroup = groups
+	}
+}
+
+// WithConcurrency parses the top-level fields of the target on up to n
+// goroutines, which helps when lookups hit a slow source. Nested structs are
+// parsed by the worker that handles their field. Errors are still reported in
+// field order.
+func WithConcurrency(n int) Option {
+	return func(p *Parser) {
+		p.concurrency = n
+	}
+}
+
+// WithAllocate lets Parse accept a pointer to a struct pointer, such as
+// **Config, allocating the struct when the inner pointer is nil.
+func WithAllocate() Option {
+	return func(p *Parser) {
+		p.allocate = true
+	}
+}
+
+// WithStrictValuePrefix makes values of fields with a valuePrefix tag fail
+// when they do not start with the prefix. By default such values are used
+// unchanged.
+func WithStrictValuePrefix() Option {
+	return func(p *Parser) {
+		p.strictValuePrefix = true
+	}
+}
+
+// WithValidateDefaults converts every default tag even when its variable is
+// set, so that defaults which do not fit the field type are reported before
+// they are ever needed.
+func WithValidateDefaults() Option {
+	return func(p *Parser) {
+		p.validateDefaults = true
+	}
+}
+
+// WithAllowEmptyRequired controls whether a variable that is set but empty
+// satisfies a field without a default. It does by default; with allow set to
+// false such a field is reported as missing.
+func WithAllowEmptyRequired(allow bool) Option {
+	return func(p *Parser) {
+		p.rejectEmptyRequired = !allow
+	}
+}
+
+// WithTrimSliceElements controls whether the space around each element of a
+// list value is trimmed before conversion, so "a, b" yields "a" and "b". It
+// applies to slices of every element type and to the elements of flags,
+// variants, pairs and format:"ms" lists, and is enabled by default.
+func WithTrimSliceElements(trim bool) Option {
+	return func(p *Parser) {
+		p.keepSliceSpace = !trim
+	}
+}
+
+// WithSource adds s to the sources consulted for values. See WithSources.
+func WithSource(s Source) Option {
+	return WithSources(s)
+}
+
+// WithSources adds sources consulted in order for each variable; the first
+// source that has the key wins and a source error fails the field. Once any
+// source is configured the process environment is only consulted when
+// EnvSource is among them. Indexed slices and catch-all maps discover their
+// keys from the sources and dotenv files, so they fail unless every source
+// implements KeyedSource, as MapSource and EnvSource do.
+func WithSources(sources ...Source) Option {
+	return func(p *Parser) {
+		p.sources = append(p.sources, sources...)
+	}
+}
+
+// WithUnsetSecrets removes the variables that were read for fields tagged
+// secret:"true" from the process environment after a successful parse, so
+// that they are not inherited by child processes. Values served by other
+// sources or dotenv files are left in place.
+func WithUnsetSecrets() Option {
+	return func(p *Parser) {
+		p.unsetSecrets = true
+	}
+}
+
+// WithFreeze marks each struct it parses as frozen, after which a further
+// Parse of the same pointer with WithFreeze fails instead of repopulating it.
+// A struct whose parse fails is not frozen. The marks are shared by all
+// parsers and keep the struct reachable until it is released with Unfreeze.
+func WithFreeze() Option {
+	return func(p *Parser) {
+		p.freezeTargets = true
+	}
+}
+
+// WithRedactor sets a function that masks values before they appear in
+// errors, warnings and snapshots, e.g. to hide anything whose key contains
+// "TOKEN". It receives the variable name and the value and returns the text
+// to show in its place.
+func WithRedactor(redactor func(key, value string) string) Option {
+	return func(p *Parser) {
+		p.redactor = redactor
+	}
+}
+
+// WithRequiredPrefixes limits required fields to those whose variable name,
+// including any struct prefix, starts with one of prefixes. Other fields
+// without a default are left unchanged when their variable is unset.
+func WithRequiredPrefixes(prefixes []string) Option {
+	return func(p *Parser) {
+		p.requiredPrefixes = append([]string{}, prefixes...)
+	}
+}
+
+// WithMapEnviron makes the parser read variables only from environ, a copy
+// of which replaces the process environment and any configured sources, for
+// hermetic tests. Dotenv files given with WithDotenv are still read.
+func WithMapEnviron(environ map[string]string) Option {
+	return func(p *Parser) {
+		p.environ = make(map[string]string, len(environ))
+		for k, v := range environ {
+			p.environ[k] = v
+		}
+	}
+}
+
+// WithStrictTypes fails on fields whose type the parser cannot convert,
+// naming the field and type, instead of silently leaving them unchanged. It
+// is meant for development, to catch a missing encoding tag or converter.
+func WithStrictTypes() Option {
+	return func(p *Parser) {
+		p.strictTypes = true
+	}
+}
+
+// WithDottedPaths derives the keys of fields without an env tag from the
+// lower-cased field names joined by ".", so that Config.DB.Host is read from
+// "db.host". A path tag overrides the derived name of a field or nested
+// struct; path tags work without this option too.
+func WithDottedPaths() Option {
+	return func(p *Parser) {
+		p.dottedPaths = true
+	}
+}
+
+// WithDecryptor sets the function that turns the values of fields tagged
+// encrypted:"true" into plaintext before they are converted, e.g. by calling
+// a KMS. Defaults are used as they are.
+func WithDecryptor(decrypt func(key, ciphertext string) (string, error)) Option {
+	return func(p *Parser) {
+		p.decryptor = decrypt
+	}
+}
@@ -0,0 +1,671 @@
+package envparser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_WithKeyNormalization(t *testing.T) {
+	t.Setenv("APP.PORT", "8080")
+	t.Setenv("app-host", "localhost")
+	type Env struct {
+		Port int    `env:"APP_PORT"`
+		Host string `env:"APP_HOST"`
+	}
+	var env Env
+	err := Parse(&env, WithKeyNormalization())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 8080)
+	assert.Equal(t, env.Host, "localhost")
+}
+
+func TestParse_WithoutKeyNormalization_Error(t *testing.T) {
+	t.Setenv("APP.PORT", "8080")
+	type Env struct {
+		Port int `env:"APP_PORT"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_WithRequireTags(t *testing.T) {
+	t.Setenv("NAME", "app")
+	type Env struct {
+		Name    string `env:"NAME"`
+		Ignored string `env:"-"`
+	}
+	var env Env
+	err := Parse(&env, WithRequireTags())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Name, "app")
+}
+
+func TestParse_WithRequireTags_Error(t *testing.T) {
+	t.Setenv("NAME", "app")
+	type Env struct {
+		Name      string `env:"NAME"`
+		Forgotten string
+	}
+	var env Env
+	err := Parse(&env, WithRequireTags())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Forgotten")
+}
+
+func TestParse_WithNilEmpty(t *testing.T) {
+	t.Setenv("TAGS", "")
+	type Env struct {
+		Tags []string `env:"TAGS"`
+	}
+	var env Env
+	err := Parse(&env, WithNilEmpty())
+	assert.NoError(t, err)
+	assert.Nil(t, env.Tags)
+}
+
+func TestParse_WithMaxDepth(t *testing.T) {
+	t.Setenv("INNER", "inner")
+	type Inner struct {
+		Value string `env:"INNER"`
+	}
+	type Middle struct {
+		Inner Inner
+	}
+	type Env struct {
+		Middle Middle
+	}
+	var env Env
+	err := Parse(&env, WithMaxDepth(2))
+	assert.NoError(t, err)
+	assert.Equal(t, env.Middle.Inner.Value, "inner")
+}
+
+func TestParse_WithMaxDepth_Error(t *testing.T) {
+	t.Setenv("INNER", "inner")
+	type Inner struct {
+		Value string `env:"INNER"`
+	}
+	type Middle struct {
+		Inner Inner
+	}
+	type Env struct {
+		Middle Middle
+	}
+	var env Env
+	err := Parse(&env, WithMaxDepth(1))
+	assert.Error(t, err)
+}
+
+func TestParse_WithDefaultFunc(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	t.Setenv("DEFAULTFUNC_HOST", "")
+	os.Unsetenv("DEFAULTFUNC_HOST")
+	type Env struct {
+		Hostname string `env:"DEFAULTFUNC_HOST"`
+		Port     int    `env:"PORT"`
+		Region   string `env:"REGION" default:"eu"`
+	}
+	called := map[string]bool{}
+	fn := func(key, val string) func() (string, error) {
+		return func() (string, error) {
+			called[key] = true
+			return val, nil
+		}
+	}
+	var env Env
+	err := Parse(&env,
+		WithDefaultFunc("DEFAULTFUNC_HOST", fn("DEFAULTFUNC_HOST", "box-1")),
+		WithDefaultFunc("PORT", fn("PORT", "8080")),
+		WithDefaultFunc("REGION", fn("REGION", "us")),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Hostname, "box-1")
+	assert.Equal(t, env.Port, 9090)
+	assert.Equal(t, env.Region, "eu")
+	assert.Equal(t, called, map[string]bool{"DEFAULTFUNC_HOST": true})
+}
+
+func TestParse_WithDefaultFunc_Error(t *testing.T) {
+	type Env struct {
+		Secret string `env:"SECRET"`
+	}
+	var env Env
+	err := Parse(&env, WithDefaultFunc("SECRET", func() (string, error) {
+		return "", errors.New("no entropy")
+	}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no entropy")
+}
+
+func TestParse_RelativeTime(t *testing.T) {
+	t.Setenv("START", "+1h")
+	t.Setenv("SINCE", "-30m")
+	base := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	type Env struct {
+		Start time.Time `env:"START" format:"relative"`
+		Since time.Time `env:"SINCE" format:"relative"`
+	}
+	var env Env
+	err := Parse(&env, WithNow(func() time.Time { return base }))
+	assert.NoError(t, err)
+	assert.Equal(t, env.Start, base.Add(time.Hour))
+	assert.Equal(t, env.Since, base.Add(-30*time.Minute))
+}
+
+func TestParse_RelativeTime_Error(t *testing.T) {
+	t.Setenv("START", "tomorrow")
+	type Env struct {
+		Start time.Time `env:"START" format:"relative"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_WithConcurrency(t *testing.T) {
+	const n = 64
+	fields := make([]reflect.StructField, n)
+	for i := range fields {
+		key := fmt.Sprintf("CONCURRENT_%d", i)
+		t.Setenv(key, strconv.Itoa(i))
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Field%d", i),
+			Type: reflect.TypeOf(0),
+			Tag:  reflect.StructTag(fmt.Sprintf(`env:"%s" deprecated:"old"`, key)),
+		}
+	}
+	v := reflect.New(reflect.StructOf(fields)).Elem()
+
+	p := New(WithConcurrency(8))
+	err := p.ParseValue(v)
+	assert.NoError(t, err)
+	for i := 0; i < n; i++ {
+		assert.Equal(t, v.Field(i).Interface(), i)
+	}
+	assert.Len(t, p.Warnings(), n)
+}
+
+func TestParse_WithConcurrency_Error(t *testing.T) {
+	t.Setenv("A", "1")
+	t.Setenv("B", "x")
+	t.Setenv("C", "y")
+	type Env struct {
+		A int `env:"A"`
+		B int `env:"B"`
+		C int `env:"C"`
+	}
+	var env Env
+	err := Parse(&env, WithConcurrency(3))
+	assert.Error(t, err)
+	assert.Equal(t, env.A, 1)
+	pe, ok := err.(*ParseError)
+	assert.True(t, ok)
+	assert.Len(t, pe.Errors, 2)
+	assert.Equal(t, pe.Errors[0].(*FieldError).Key, "B")
+	assert.Equal(t, pe.Errors[1].(*FieldError).Key, "C")
+}
+
+func TestParse_WithValidateDefaults(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	type Env struct {
+		Port int `env:"PORT" default:"8080"`
+	}
+	var env Env
+	err := Parse(&env, WithValidateDefaults())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 9090)
+}
+
+func TestParse_WithValidateDefaults_Error(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	type Env struct {
+		Port int `env:"PORT" default:"eighty"`
+	}
+	var env Env
+	assert.NoError(t, Parse(&env))
+
+	err := Parse(&env, WithValidateDefaults())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid default "eighty"`)
+}
+
+func TestParse_WithAllowEmptyRequired(t *testing.T) {
+	t.Setenv("NAME", "")
+	type Env struct {
+		Name string `env:"NAME"`
+	}
+	var env Env
+	err := Parse(&env, WithAllowEmptyRequired(true))
+	assert.NoError(t, err)
+	assert.Equal(t, env.Name, "")
+}
+
+func TestParse_WithAllowEmptyRequired_Error(t *testing.T) {
+	t.Setenv("NAME", "")
+	t.Setenv("MODE", "")
+	type Env struct {
+		Name string `env:"NAME"`
+	}
+	var env Env
+	err := Parse(&env, WithAllowEmptyRequired(false))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required environment variables: NAME")
+
+	type WithDefault struct {
+		Mode string `env:"MODE" default:"dev"`
+	}
+	var withDefault WithDefault
+	err = Parse(&withDefault, WithAllowEmptyRequired(false))
+	assert.NoError(t, err)
+	assert.Equal(t, withDefault.Mode, "")
+}
+
+func TestParse_WithTrimSliceElements(t *testing.T) {
+	t.Setenv("TAGS", " a, b ,c ")
+	t.Setenv("PORTS", "80, 443")
+	type Env struct {
+		Tags  []string `env:"TAGS"`
+		Ports []int    `env:"PORTS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Tags, []string{"a", "b", "c"})
+	assert.Equal(t, env.Ports, []int{80, 443})
+
+	t.Setenv("NAMES", "a, ,b")
+	var kept struct {
+		Tags  []string `env:"TAGS"`
+		Names []string `env:"NAMES" skipEmpty:"true"`
+	}
+	err = Parse(&kept, WithTrimSliceElements(false))
+	assert.NoError(t, err)
+	assert.Equal(t, kept.Tags, []string{" a", " b ", "c "})
+	assert.Equal(t, kept.Names, []string{"a", " ", "b"})
+}
+
+func TestParse_WithTrimSliceElements_Error(t *testing.T) {
+	t.Setenv("PORTS", "80, 443")
+	type Env struct {
+		Ports []int `env:"PORTS"`
+	}
+	var env Env
+	err := Parse(&env, WithTrimSliceElements(false))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `element 1 (" 443")`)
+
+	t.Setenv("LATENCIES", "100, 250")
+	t.Setenv("PERMS", "read, write")
+	t.Setenv("WEIGHTS", "a:3,b: 1")
+	type Lists struct {
+		Latencies []time.Duration `env:"LATENCIES" format:"ms"`
+		Perms     testPerm        `env:"PERMS" flags:"PERMS"`
+		Weights   []struct {
+			Name   string
+			Weight int
+		} `env:"WEIGHTS" encoding:"pairs"`
+	}
+	var lists Lists
+	assert.NoError(t, Parse(&lists))
+	err = Parse(&lists, WithTrimSliceElements(false))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `env 'LATENCIES': element 1 (" 250")`)
+	assert.Contains(t, err.Error(), `env 'PERMS': unknown PERMS flag " write"`)
+	assert.Contains(t, err.Error(), `env 'WEIGHTS': pair "b: 1"`)
+}
+
+func TestParse_WithUnsetSecrets(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "hunter2")
+	t.Setenv("DB_HOST", "localhost")
+	type Env struct {
+		Password string `env:"DB_PASSWORD" secret:"true"`
+		Host     string `env:"DB_HOST"`
+	}
+	var env Env
+	err := Parse(&env, WithUnsetSecrets())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Password, "hunter2")
+	_, ok := os.LookupEnv("DB_PASSWORD")
+	assert.False(t, ok)
+	assert.Equal(t, os.Getenv("DB_HOST"), "localhost")
+}
+
+func TestParse_WithUnsetSecrets_NormalizedKey(t *testing.T) {
+	t.Setenv("db-password", "hunter2")
+	type Env struct {
+		Password string `env:"DB_PASSWORD" secret:"true"`
+	}
+	var env Env
+	err := Parse(&env, WithUnsetSecrets(), WithKeyNormalization())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Password, "hunter2")
+	_, ok := os.LookupEnv("db-password")
+	assert.False(t, ok)
+}
+
+func TestParse_WithUnsetSecrets_IndexedAndCatchAll(t *testing.T) {
+	t.Setenv("ITEM_0", "k0")
+	t.Setenv("ITEM_1", "k1")
+	t.Setenv("VAULT_A", "va")
+	t.Setenv("PLAIN_0", "p0")
+	type Env struct {
+		Items []string          `env:"ITEM" indexed:"true" secret:"true"`
+		Plain []string          `env:"PLAIN" indexed:"true"`
+		Vault map[string]string `env:"*" prefix:"VAULT_" secret:"true"`
+	}
+	var env Env
+	err := Parse(&env, WithUnsetSecrets())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Items, []string{"k0", "k1"})
+	assert.Equal(t, env.Vault, map[string]string{"VAULT_A": "va"})
+	for _, key := range []string{"ITEM_0", "ITEM_1", "VAULT_A"} {
+		_, ok := os.LookupEnv(key)
+		assert.False(t, ok, key)
+	}
+	assert.Equal(t, os.Getenv("PLAIN_0"), "p0")
+}
+
+func TestParse_WithUnsetSecrets_OtherSource(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "process-value")
+	type Env struct {
+		Password string `env:"DB_PASSWORD" secret:"true"`
+	}
+	var env Env
+	err := Parse(&env, WithUnsetSecrets(), WithSource(MapSource{"DB_PASSWORD": "hunter2"}))
+	assert.NoError(t, err)
+	assert.Equal(t, env.Password, "hunter2")
+	assert.Equal(t, os.Getenv("DB_PASSWORD"), "process-value")
+}
+
+func TestParse_WithUnsetSecrets_Error(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "hunter2")
+	type Env struct {
+		Password string `env:"DB_PASSWORD" secret:"true"`
+		Port     int    `env:"DB_PORT"`
+	}
+	var env Env
+	err := Parse(&env, WithUnsetSecrets())
+	assert.Error(t, err)
+	assert.Equal(t, os.Getenv("DB_PASSWORD"), "hunter2")
+}
+
+func TestParse_WithRedactor(t *testing.T) {
+	t.Setenv("API_TOKEN", "s3cr3t-value")
+	t.Setenv("RETRIES", "many")
+	t.Setenv("TOKEN_IDS", "1,abc")
+	redactor := func(key, value string) string {
+		if strings.Contains(key, "TOKEN") {
+			return "[redacted]"
+		}
+		return value
+	}
+	type Env struct {
+		Token    int   `env:"API_TOKEN"`
+		Retries  int   `env:"RETRIES"`
+		TokenIDs []int `env:"TOKEN_IDS"`
+	}
+	var env Env
+	err := Parse(&env, WithRedactor(redactor))
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "s3cr3t-value")
+	assert.NotContains(t, err.Error(), "abc")
+	assert.Contains(t, err.Error(), `env 'API_TOKEN': strconv.Atoi: parsing "[redacted]"`)
+	assert.Contains(t, err.Error(), `"many"`)
+
+	var chain []error
+	for pending := []error{err}; len(pending) > 0; pending = pending[1:] {
+		e := pending[0]
+		chain = append(chain, e)
+		switch u := e.(type) {
+		case interface{ Unwrap() []error }:
+			pending = append(pending, u.Unwrap()...)
+		case interface{ Unwrap() error }:
+			if next := u.Unwrap(); next != nil {
+				pending = append(pending, next)
+			}
+		}
+	}
+	var sawSyntax bool
+	for _, e := range chain {
+		assert.NotContains(t, e.Error(), "s3cr3t-value")
+		assert.NotContains(t, e.Error(), "abc")
+		if numErr, ok := e.(*strconv.NumError); ok {
+			assert.NotEqual(t, numErr.Num, "s3cr3t-value")
+		}
+		sawSyntax = sawSyntax || e == strconv.ErrSyntax
+	}
+	assert.True(t, sawSyntax)
+
+	type Snapshot struct {
+		Token string `env:"API_TOKEN"`
+	}
+	snapshot, err := New(WithRedactor(redactor)).ParseWithSnapshot(&Snapshot{})
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, map[string]string{"API_TOKEN": "[redacted]"})
+}
+
+func TestParse_WithRequiredPrefixes(t *testing.T) {
+	t.Setenv("DB_HOST", "localhost")
+	type Cache struct {
+		URL string `env:"URL"`
+	}
+	type Env struct {
+		DBHost  string `env:"DB_HOST"`
+		Metrics string `env:"METRICS_URL"`
+		Cache   Cache  `prefix:"CACHE_"`
+	}
+	var env Env
+	err := Parse(&env, WithRequiredPrefixes([]string{"DB_"}))
+	assert.NoError(t, err)
+	assert.Equal(t, env.DBHost, "localhost")
+	assert.Equal(t, env.Metrics, "")
+}
+
+func TestParse_WithRequiredPrefixes_Error(t *testing.T) {
+	type Cache struct {
+		URL string `env:"URL"`
+	}
+	type Env struct {
+		DBHost  string `env:"DB_HOST"`
+		Metrics string `env:"METRICS_URL"`
+		Cache   Cache  `prefix:"CACHE_"`
+	}
+	var env Env
+	err := Parse(&env, WithRequiredPrefixes([]string{"DB_", "CACHE_"}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required environment variables: DB_HOST, CACHE_URL\n")
+}
+
+func TestParse_WithMapEnviron(t *testing.T) {
+	t.Setenv("HOST", "from-os")
+	t.Setenv("APP_FROM_OS", "1")
+	type DB struct {
+		Port int `env:"PORT"`
+	}
+	type Env struct {
+		Host    string            `env:"HOST"`
+		Tags    []string          `env:"TAG" indexed:"true"`
+		DB      DB                `prefix:"DB_"`
+		Timeout time.Duration     `env:"TIMEOUT" default:"5s"`
+		Extra   map[string]string `env:"*" prefix:"APP_"`
+	}
+	var env Env
+	err := Parse(&env, WithMapEnviron(map[string]string{
+		"HOST":      "from-map",
+		"TAG_0":     "a",
+		"TAG_1":     "b",
+		"DB_PORT":   "5432",
+		"APP_COLOR": "blue",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, env, Env{
+		Host:    "from-map",
+		Tags:    []string{"a", "b"},
+		DB:      DB{Port: 5432},
+		Timeout: 5 * time.Second,
+		Extra:   map[string]string{"APP_COLOR": "blue"},
+	})
+}
+
+func TestParse_WithMapEnviron_Error(t *testing.T) {
+	t.Setenv("HOST", "from-os")
+	type Env struct {
+		Host string `env:"HOST"`
+	}
+	var env Env
+	err := Parse(&env, WithMapEnviron(map[string]string{}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required environment variables: HOST")
+}
+
+func TestParse_WithStrictTypes(t *testing.T) {
+	t.Setenv("SERVERS", `[{"Host":"a"}]`)
+	t.Setenv("PORT", "8080")
+	type Server struct {
+		Host string
+	}
+	type Env struct {
+		Servers []Server `env:"SERVERS" encoding:"json"`
+		Port    int      `env:"PORT"`
+	}
+	var env Env
+	err := Parse(&env, WithStrictTypes())
+	assert.NoError(t, err)
+	assert.Equal(t, env.Servers, []Server{{Host: "a"}})
+}
+
+func TestParse_WithStrictTypes_Error(t *testing.T) {
+	t.Setenv("SERVERS", `[{"Host":"a"}]`)
+	t.Setenv("HANDLER", "noop")
+	type Server struct {
+		Host string
+	}
+	type Env struct {
+		Servers []Server `env:"SERVERS"`
+		Handler func()   `env:"HANDLER"`
+	}
+	var env Env
+	assert.NoError(t, Parse(&env))
+
+	err := Parse(&env, WithStrictTypes())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "field Servers has unsupported type []envparser.Server")
+	assert.Contains(t, err.Error(), "field Handler has unsupported type func()")
+}
+
+func TestParse_WithDottedPaths(t *testing.T) {
+	type Pool struct {
+		Size    int
+		Timeout time.Duration `path:"timeout_ms" format:"ms"`
+	}
+	type Database struct {
+		Host string
+		Port int
+		Pool Pool
+	}
+	type Env struct {
+		Name string
+		DB   Database `path:"db"`
+	}
+	var env Env
+	err := Parse(&env, WithDottedPaths(), WithMapEnviron(map[string]string{
+		"name":               "app",
+		"db.host":            "localhost",
+		"db.port":            "5432",
+		"db.pool.size":       "10",
+		"db.pool.timeout_ms": "250",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, env, Env{
+		Name: "app",
+		DB: Database{
+			Host: "localhost",
+			Port: 5432,
+			Pool: Pool{Size: 10, Timeout: 250 * time.Millisecond},
+		},
+	})
+}
+
+func TestParse_PathTags(t *testing.T) {
+	type Database struct {
+		Host string `path:"host"`
+		Port int
+	}
+	type Env struct {
+		DB Database `path:"db"`
+	}
+	var env Env
+	err := Parse(&env, WithMapEnviron(map[string]string{"db.host": "localhost", "db.port": "5432"}))
+	assert.NoError(t, err)
+	assert.Equal(t, env.DB, Database{Host: "localhost"})
+}
+
+func TestParse_WithDottedPaths_Error(t *testing.T) {
+	type Database struct {
+		Host string
+	}
+	type Env struct {
+		DB Database
+	}
+	var env Env
+	err := Parse(&env, WithDottedPaths(), WithMapEnviron(map[string]string{"db.hostname": "localhost"}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required environment variables: db.host")
+}
+
+func TestParse_WithDecryptor(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "enc:2retnuh")
+	t.Setenv("DB_PORT", "enc:2345")
+	t.Setenv("DB_HOST", "enc:plain")
+	reverse := func(key, ciphertext string) (string, error) {
+		if !strings.HasPrefix(ciphertext, "enc:") {
+			return "", fmt.Errorf("%s is not encrypted", key)
+		}
+		runes := []rune(strings.TrimPrefix(ciphertext, "enc:"))
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes), nil
+	}
+	type Env struct {
+		Password string `env:"DB_PASSWORD" encrypted:"true"`
+		Port     int    `env:"DB_PORT" encrypted:"true"`
+		Host     string `env:"DB_HOST"`
+		User     string `env:"DB_USER" encrypted:"true" default:"admin"`
+	}
+	var env Env
+	err := Parse(&env, WithDecryptor(reverse))
+	assert.NoError(t, err)
+	assert.Equal(t, env.Password, "hunter2")
+	assert.Equal(t, env.Port, 5432)
+	assert.Equal(t, env.Host, "enc:plain")
+	assert.Equal(t, env.User, "admin")
+}
+
+func TestParse_WithDecryptor_Error(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "hunter2")
+	failing := func(key, ciphertext string) (string, error) {
+		return "", errors.New("kms: access denied")
+	}
+	type Env struct {
+		Password string `env:"DB_PASSWORD" encrypted:"true"`
+	}
+	var env Env
+	err := Parse(&env, WithDecryptor(failing))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "env 'DB_PASSWORD': decrypt: kms: access denied")
+	assert.NotContains(t, err.Error(), "hunter2")
+
+	err = Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "encrypted field requires WithDecryptor")
+}
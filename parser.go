@@ -1,75 +1,758 @@
 package envparser
 
 import (
-	"encoding/base64"
+	"database/sql"
+	"encoding"
 	"encoding/json"
-	"encoding/xml"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/url"
 	"os"
+	"os/user"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-func Parse(target interface{}) error {
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// Defaulter is implemented by structs that set programmatic defaults. Parse
+// calls SetDefaults on the target and every nested struct before reading the
+// environment, so environment values override the defaults and fields left
+// non-zero by SetDefaults are not reported as missing.
+type Defaulter interface {
+	SetDefaults()
+}
+
+// EnvUnmarshaler is implemented by types that parse themselves from the raw
+// value of an environment variable. Slices of such types are parsed element
+// by element from a comma-separated list.
+type EnvUnmarshaler interface {
+	UnmarshalEnv(value string) error
+}
+
+var envUnmarshalerType = reflect.TypeOf((*EnvUnmarshaler)(nil)).Elem()
+
+// scannerType is sql.Scanner; types implementing it that match no built-in
+// conversion are passed the raw value as a string.
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// typeParsers converts values into types that are only available on some Go
+// releases, such as those registered in netip.go. Slices of these types are
+// parsed element by element.
+var typeParsers = map[reflect.Type]func(string) (interface{}, error){}
+
+// Parser populates structs from environment variables according to its
+// options. Use New to create one.
+type Parser struct {
+	normalizeKeys       bool
+	requireTags         bool
+	nilEmpty            bool
+	sparseSlices        bool
+	ignoreUnknownKV     bool
+	allocate            bool
+	strictValuePrefix   bool
+	validateDefaults    bool
+	rejectEmptyRequired bool
+	keepSliceSpace      bool
+	unsetSecrets        bool
+	freezeTargets       bool
+	redactor            func(key, value string) string
+	requiredPrefixes    []string
+	strictTypes         bool
+	dottedPaths         bool
+	decryptor           func(key, ciphertext string) (string, error)
+	lookupUser          func(name string) (*user.User, error)
+	lookupGroup         func(name string) (*user.Group, error)
+	maxDepth            int
+	concurrency         int
+	errorPrefix         string
+	now                 func() time.Time
+	defaultFuncs        map[string]func() (string, error)
+
+	dotenvFiles    []string
+	dotenvOverride bool
+	dotenv         map[string]string
+	sources        []Source
+	environ        map[string]string
+	stats          Stats
+	report         Report
+	raw            map[string]string
+	secrets        map[string]bool
+	envRead        map[string]bool
+
+	mu       sync.Mutex
+	warnings []string
+	groups   map[string]*group
+	consumed map[string]bool
+	catchAll []catchAll
+	fields   []parsedField
+}
+
+// New returns a Parser configured with opts.
+func New(opts ...Option) *Parser {
+	p := &Parser{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Parse populates target, which must be a pointer to a struct, from the
+// environment using a Parser configured with opts.
+func Parse(target interface{}, opts ...Option) error {
+	return New(opts...).Parse(target)
+}
+
+// Parse populates target, which must be a pointer to a struct, from the
+// environment.
+func (p *Parser) Parse(target interface{}) error {
 	val := reflect.ValueOf(target)
+	if !val.IsValid() {
+		return errors.New("target must be a pointer to a struct, got nil")
+	}
+	if val.Kind() == reflect.Ptr && val.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer to a struct, got nil %s", val.Type())
+	}
+	if p.allocate && val.Kind() == reflect.Ptr && val.Elem().Kind() == reflect.Ptr && val.Elem().Type().Elem().Kind() == reflect.Struct {
+		if val.Elem().IsNil() {
+			val.Elem().Set(reflect.New(val.Elem().Type().Elem()))
+		}
+		val = val.Elem()
+	}
 	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
 		return errors.New("target must be a pointer to a struct")
 	}
-	v := val.Elem()
+	return p.ParseValue(val.Elem())
+}
+
+// ParseValue populates v, which must be an addressable struct value, from the
+// environment using a Parser configured with opts. It lets other libraries
+// delegate to this package without building a pointer to the struct.
+func ParseValue(v reflect.Value, opts ...Option) error {
+	return New(opts...).ParseValue(v)
+}
+
+// ParseValue populates v, which must be an addressable struct value, from the
+// environment.
+func (p *Parser) ParseValue(v reflect.Value) error {
+	if v.Kind() != reflect.Struct || !v.CanAddr() {
+		return errors.New("value must be an addressable struct")
+	}
+	p.warnings = nil
+	p.groups = nil
+	p.consumed = nil
+	p.catchAll = nil
+	p.fields = nil
+	p.stats = Stats{}
+	p.report = Report{}
+	p.raw = nil
+	p.secrets = nil
+	p.envRead = nil
+	if err := p.loadDotenv(); err != nil {
+		return err
+	}
+	if err := p.freeze(v); err != nil {
+		return p.brandError(err)
+	}
+
+	err := p.parseStruct(v, 0, "")
+	p.fillCatchAll()
+	if groupErrs := p.checkGroups(); len(groupErrs) > 0 {
+		switch e := err.(type) {
+		case nil:
+			err = &ParseError{Errors: groupErrs}
+		case *ParseError:
+			e.Errors = append(e.Errors, groupErrs...)
+		}
+	}
+	if err == nil && p.unsetSecrets {
+		p.unsetSecretKeys()
+	}
+	if err != nil {
+		p.unfreeze(v)
+	}
+	return p.brandError(err)
+}
+
+// Warnings returns the non-fatal issues recorded by the most recent call to
+// Parse, such as values that were replaced by their default or deprecated
+// variables that are still set.
+func (p *Parser) Warnings() []string {
+	return p.warnings
+}
+
+// parseStruct populates the fields of the struct v. prefix is prepended to
+// every env key, which is how elements of indexed struct slices are read.
+func (p *Parser) parseStruct(v reflect.Value, depth int, prefix string) error {
 	t := v.Type()
+	if p.maxDepth > 0 && depth > p.maxDepth {
+		return fmt.Errorf("struct %s exceeds maximum depth of %d", t, p.maxDepth)
+	}
 
-	var errs []error
+	d, hasDefaults := v.Addr().Interface().(Defaulter)
+	if hasDefaults {
+		d.SetDefaults()
+	}
 
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		fieldType := t.Field(i)
+	results := make([]fieldResult, v.NumField())
+	if depth == 0 && p.concurrency > 1 {
+		p.parseFieldsConcurrently(v, prefix, hasDefaults, results)
+	} else {
+		for i := range results {
+			results[i] = p.parseField(v, i, depth, prefix, hasDefaults)
+			if results[i].abort {
+				break
+			}
+		}
+	}
 
-		if !field.CanSet() {
+	var errs []error
+	for _, r := range results {
+		if r.err == nil {
 			continue
 		}
+		if r.abort {
+			return r.err
+		}
+		if pe, ok := r.err.(*ParseError); ok {
+			errs = append(errs, pe.Errors...)
+			continue
+		}
+		errs = append(errs, r.err)
+	}
+
+	if len(errs) > 0 {
+		return &ParseError{Errors: errs}
+	}
+
+	return nil
+}
+
+// fieldResult is the outcome of parsing a single field. An error with abort
+// set stops the parse instead of being aggregated with the others.
+type fieldResult struct {
+	err   error
+	abort bool
+}
+
+// parseFieldsConcurrently parses the fields of v on a pool of workers bounded
+// by the configured concurrency. Each worker writes only the results of the
+// fields it parses, so results needs no further locking.
+func (p *Parser) parseFieldsConcurrently(v reflect.Value, prefix string, hasDefaults bool, results []fieldResult) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < p.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = p.parseField(v, i, 0, prefix, hasDefaults)
+			}
+		}()
+	}
+	for i := range results {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+func (p *Parser) parseField(v reflect.Value, i, depth int, prefix string, hasDefaults bool) fieldResult {
+	field := v.Field(i)
+	fieldType := v.Type().Field(i)
+
+	if !field.CanSet() {
+		return fieldResult{}
+	}
+
+	tag := fieldType.Tag
+	envKey := tag.Get("env")
+	if _, hasEnv := tag.Lookup("env"); !hasEnv && !fieldType.Anonymous &&
+		(fieldType.Type.Kind() != reflect.Struct || isScalarStruct(fieldType.Type)) {
+		envKey = p.pathSegment(fieldType)
+	}
+	envKey, envOpts := splitEnvTag(envKey)
+	optional := envOpts["optional"] || tag.Get("optional") == "true"
+	keySpecs := parseKeySpecs(envKey)
+	envKey = keySpecs[0].key
+
+	// Handle embedded/anonymous structs
+	if fieldType.Anonymous || (fieldType.Type.Kind() == reflect.Struct && (envKey == "" || envKey == "-")) {
+		nestedPrefix := prefix + tag.Get("prefix")
+		if segment := p.pathSegment(fieldType); segment != "" && !fieldType.Anonymous {
+			nestedPrefix += segment + "."
+		}
+		if fallbackKey, ok := tag.Lookup("jsonFallback"); ok && !p.anyEnvSet(fieldType.Type, nestedPrefix) {
+			fallbackKey = prefix + fallbackKey
+			raw, ok, err := p.lookup(fallbackKey)
+			if err != nil {
+				return fieldResult{err: &FieldError{Key: fallbackKey, Err: err}}
+			}
+			if ok {
+				if err := json.Unmarshal([]byte(raw), field.Addr().Interface()); err != nil {
+					return fieldResult{err: &FieldError{Key: fallbackKey, Err: err}}
+				}
+				return fieldResult{}
+			}
+		}
+		if err := p.parseStruct(field, depth+1, nestedPrefix); err != nil {
+			_, fieldErrs := err.(*ParseError)
+			return fieldResult{err: err, abort: !fieldErrs}
+		}
+		return fieldResult{}
+	}
 
-		tag := fieldType.Tag
-		envKey := tag.Get("env")
+	if envKey == "*" {
+		if err := p.addCatchAll(field, prefix+tag.Get("prefix"), tag.Get("secret") == "true"); err != nil {
+			return fieldResult{err: fmt.Errorf("field '%s': %v", fieldType.Name, err)}
+		}
+		return fieldResult{}
+	}
+	if envKey == "" || envKey == "-" {
+		if envKey == "" && p.requireTags {
+			return fieldResult{err: fmt.Errorf("field '%s' has no env tag", fieldType.Name)}
+		}
+		return fieldResult{}
+	}
+	envKey = prefix + envKey
 
-		// Handle embedded/anonymous structs
-		if fieldType.Anonymous || (fieldType.Type.Kind() == reflect.Struct && (envKey == "" || envKey == "-")) {
-			if err := Parse(field.Addr().Interface()); err != nil {
-				return err
+	if tag.Get("secret") == "true" {
+		for _, spec := range keySpecs {
+			p.markSecret(prefix + spec.key)
+		}
+	}
+
+	if cond, ok := tag.Lookup("when"); ok {
+		met, err := p.conditionMet(cond)
+		if err != nil {
+			return fieldResult{err: &FieldError{Key: envKey, Err: err}}
+		}
+		if !met {
+			return fieldResult{}
+		}
+	}
+
+	if tag.Get("indexed") == "true" && field.Kind() == reflect.Slice {
+		n, read, err := p.parseIndexed(field, fieldType, envKey, depth)
+		if err != nil {
+			return fieldResult{err: err}
+		}
+		if n > 0 {
+			if err := validate(field, tag); err != nil {
+				return fieldResult{err: &FieldError{Key: envKey, Err: err}}
+			}
+			p.recordField(envKey, read, field, fieldType)
+			p.countField(envKey, false, false)
+			return fieldResult{}
+		}
+	}
+
+	defaultVal, hasDefault := tag.Lookup("default")
+
+	source, val, ok, err := p.lookupFirst(prefix, keySpecs)
+	if err != nil {
+		return fieldResult{err: &FieldError{Key: envKey, Err: err}}
+	}
+	if tag.Get("presence") == "true" && field.Kind() == reflect.Bool {
+		field.SetBool(ok)
+		p.countField(envKey, false, !ok)
+		if ok {
+			p.recordField(envKey, []string{source}, field, fieldType)
+		}
+		return fieldResult{}
+	}
+	if tag.Get("negatable") == "true" && field.Kind() == reflect.Bool {
+		negKey := "NO_" + envKey
+		neg, negated, err := p.lookup(negKey)
+		if err != nil {
+			return fieldResult{err: &FieldError{Key: negKey, Err: err}}
+		}
+		if negated {
+			off, err := strconv.ParseBool(strings.TrimSpace(neg))
+			if err != nil {
+				return fieldResult{err: &FieldError{Key: negKey, Err: err}}
+			}
+			if off {
+				val, ok = "false", true
 			}
-			continue
 		}
+	}
+	if ok && val == "" && !hasDefault && p.rejectEmptyRequired {
+		ok = false
+	}
+	if name, grouped := tag.Lookup("group"); grouped {
+		p.recordGroup(name, envKey, ok, tag.Get("groupMode") == "exactlyOne")
+		if !ok && !hasDefault {
+			p.countField(envKey, false, true)
+			return fieldResult{}
+		}
+	}
+	if fn, found := p.defaultFuncs[envKey]; !ok && !hasDefault && found {
+		v, err := fn()
+		if err != nil {
+			return fieldResult{err: &FieldError{Key: envKey, Err: fmt.Errorf("default: %v", err)}}
+		}
+		defaultVal, hasDefault = v, true
+	}
+	p.countField(envKey, !ok && hasDefault, !ok && !hasDefault)
+	if ok && hasDefault {
+		p.unusedDefault(envKey)
+	}
+	if !ok {
+		if !hasDefault {
+			if (hasDefaults && !isZero(field)) || optional || !p.isRequired(envKey) {
+				return fieldResult{}
+			}
+			return fieldResult{err: &FieldError{Key: envKey, Err: ErrMissing}}
+		}
+		val = defaultVal
+	}
+	if p.validateDefaults && ok && hasDefault {
+		scratch := reflect.New(field.Type()).Elem()
+		if err := p.setValueFromEnv(scratch, fieldType, defaultVal); err != nil {
+			return fieldResult{err: &FieldError{Key: envKey, Err: fmt.Errorf("invalid default %q: %v", defaultVal, err)}}
+		}
+	}
+	if msg, deprecated := tag.Lookup("deprecated"); deprecated && ok {
+		p.warn(fmt.Sprintf("env '%s' is deprecated: %s", envKey, msg))
+	}
+	if valuePrefix, found := tag.Lookup("valuePrefix"); found && ok {
+		if !strings.HasPrefix(val, valuePrefix) && p.strictValuePrefix {
+			return fieldResult{err: &FieldError{Key: envKey, Err: fmt.Errorf("value does not start with %q", valuePrefix)}}
+		}
+		val = strings.TrimPrefix(val, valuePrefix)
+	}
+	if tag.Get("encrypted") == "true" && ok {
+		if p.decryptor == nil {
+			return fieldResult{err: &FieldError{Key: envKey, Err: errors.New("encrypted field requires WithDecryptor")}}
+		}
+		plaintext, err := p.decryptor(envKey, val)
+		if err != nil {
+			return fieldResult{err: &FieldError{Key: envKey, Err: fmt.Errorf("decrypt: %v", err)}}
+		}
+		val = plaintext
+	}
+	if val, err = p.interpolateFields(v, i, depth, val); err != nil {
+		return fieldResult{err: &FieldError{Key: envKey, Err: err}}
+	}
+
+	err = p.redact(envKey, val, p.setValueFromEnv(field, fieldType, val))
+	if err != nil && ok && hasDefault && tag.Get("fallbackOnError") == "true" {
+		p.warn(fmt.Sprintf("env '%s': %v, using default %q", envKey, err, defaultVal))
+		field.Set(reflect.Zero(field.Type()))
+		err = p.setValueFromEnv(field, fieldType, defaultVal)
+	}
+	if err == nil {
+		err = p.redact(envKey, val, validate(field, tag))
+	}
+	if err != nil {
+		return fieldResult{err: &FieldError{Key: envKey, Err: err}}
+	}
+	p.recordField(envKey, []string{source}, field, fieldType)
+	return fieldResult{}
+}
 
+// warn records a non-fatal issue for Warnings. It is safe for concurrent use.
+func (p *Parser) warn(msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.warnings = append(p.warnings, msg)
+}
+
+func isZero(v reflect.Value) bool {
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+// anyEnvSet reports whether any variable named by an env tag in the struct
+// type t, including its nested structs, is set.
+func (p *Parser) anyEnvSet(t reflect.Type, prefix string) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		envKey := f.Tag.Get("env")
+		if f.Type.Kind() == reflect.Struct && (envKey == "" || envKey == "-") {
+			if p.anyEnvSet(f.Type, prefix+f.Tag.Get("prefix")) {
+				return true
+			}
+			continue
+		}
 		if envKey == "" || envKey == "-" {
 			continue
 		}
+		keys, _ := splitEnvTag(envKey)
+		if _, _, ok, _ := p.lookupFirst(prefix, parseKeySpecs(keys)); ok {
+			return true
+		}
+	}
+	return false
+}
 
-		val, ok := os.LookupEnv(envKey)
-		if !ok {
-			return fmt.Errorf("missing %s environment", envKey)
+// currentTime returns the reference time for relative values.
+func (p *Parser) currentTime() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
+
+// pathSegment returns the name of fieldType within a dotted key path such as
+// "db.host": its path tag or, with WithDottedPaths, its lower-cased name.
+func (p *Parser) pathSegment(fieldType reflect.StructField) string {
+	if path, ok := fieldType.Tag.Lookup("path"); ok {
+		return path
+	}
+	if p.dottedPaths && fieldType.Tag.Get("prefix") == "" {
+		return strings.ToLower(fieldType.Name)
+	}
+	return ""
+}
+
+// splitEnvTag separates the keys of an env tag from the comma-separated
+// options that follow them, as in env:"FOO,optional".
+func splitEnvTag(envTag string) (string, map[string]bool) {
+	parts := strings.Split(envTag, ",")
+	if len(parts) == 1 {
+		return envTag, nil
+	}
+	opts := make(map[string]bool, len(parts)-1)
+	for _, opt := range parts[1:] {
+		opts[strings.TrimSpace(opt)] = true
+	}
+	return parts[0], opts
+}
+
+// isRequired reports whether a missing key is an error, which it is unless
+// WithRequiredPrefixes was given and key matches none of the prefixes.
+func (p *Parser) isRequired(key string) bool {
+	if p.requiredPrefixes == nil {
+		return true
+	}
+	for _, prefix := range p.requiredPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
 		}
+	}
+	return false
+}
 
-		if err := setValueFromEnv(field, fieldType, val); err != nil {
-			errs = append(errs, fmt.Errorf("env '%s': %v", envKey, err))
+// conditionMet evaluates a when tag of the form "KEY=value" or "KEY!=value"
+// against the variable KEY, which is compared unset as an empty value.
+func (p *Parser) conditionMet(cond string) (bool, error) {
+	kv := strings.SplitN(cond, "=", 2)
+	if len(kv) != 2 || kv[0] == "" || kv[0] == "!" {
+		return false, fmt.Errorf("invalid when condition %q, expected KEY=value or KEY!=value", cond)
+	}
+	key, negate := kv[0], strings.HasSuffix(kv[0], "!")
+	if negate {
+		key = strings.TrimSuffix(key, "!")
+	}
+	val, _, err := p.lookup(strings.TrimSpace(key))
+	if err != nil {
+		return false, err
+	}
+	return (val == kv[1]) != negate, nil
+}
+
+// lookup retrieves the value of the environment variable named by key. With
+// key normalization enabled it also tries the "_", "-" and "." separated
+// spellings of key, in both the original and lower case.
+func (p *Parser) lookup(key string) (string, bool, error) {
+	_, val, ok, err := p.resolve(key)
+	return val, ok, err
+}
+
+// resolve is lookup that also returns the name of the variable that supplied
+// the value, which differs from key when a normalized spelling matched.
+func (p *Parser) resolve(key string) (string, string, bool, error) {
+	if val, ok, err := p.lookupKey(key); ok || err != nil {
+		p.consume(key)
+		p.recordRaw(key, val, ok)
+		return key, val, ok, err
+	}
+	if p.normalizeKeys {
+		for _, k := range keyVariants(key) {
+			if val, ok, err := p.lookupKey(k); ok || err != nil {
+				p.consume(k)
+				p.recordRaw(key, val, ok)
+				return k, val, ok, err
+			}
 		}
 	}
+	return key, "", false, nil
+}
 
-	if len(errs) > 0 {
-		var builder strings.Builder
-		builder.WriteString("error parsing environment to struct:\n")
-		for _, err := range errs {
-			builder.WriteString(err.Error() + "\n")
+// lookupKey retrieves key from the configured sources, the process
+// environment by default, and any loaded dotenv files, consulting the dotenv
+// values first when they override the sources.
+func (p *Parser) lookupKey(key string) (string, bool, error) {
+	if p.dotenvOverride {
+		if val, ok := p.dotenv[key]; ok {
+			return val, true, nil
+		}
+	}
+	for _, s := range p.activeSources() {
+		val, ok, err := s.Lookup(key)
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			if _, env := s.(envSource); env {
+				p.markEnvRead(key)
+			}
+			return val, true, nil
 		}
-		return errors.New(builder.String())
 	}
+	val, ok := p.dotenv[key]
+	return val, ok, nil
+}
 
-	return nil
+func keyVariants(key string) []string {
+	parts := strings.FieldsFunc(key, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	})
+
+	var variants []string
+	seen := map[string]bool{key: true}
+	for _, sep := range []string{"_", "-", "."} {
+		joined := strings.Join(parts, sep)
+		for _, k := range []string{joined, strings.ToLower(joined)} {
+			if !seen[k] {
+				seen[k] = true
+				variants = append(variants, k)
+			}
+		}
+	}
+	return variants
 }
 
-func setValueFromEnv(field reflect.Value, fieldType reflect.StructField, val string) error {
+func (p *Parser) setValueFromEnv(field reflect.Value, fieldType reflect.StructField, val string) error {
+	if val == "" && p.nilEmpty && (field.Kind() == reflect.Slice || field.Kind() == reflect.Map) {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	if pattern, ok := fieldType.Tag.Lookup("separatorRegex"); ok {
+		if _, err := separatorRegexp(pattern); err != nil {
+			return fmt.Errorf("invalid separatorRegex: %v", err)
+		}
+	}
+
+	if u, ok := field.Addr().Interface().(EnvUnmarshaler); ok {
+		return u.UnmarshalEnv(val)
+	}
+	if field.Kind() == reflect.Slice && reflect.PtrTo(field.Type().Elem()).Implements(envUnmarshalerType) {
+		parts := p.splitList(val, fieldType.Tag)
+		slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := slice.Index(i).Addr().Interface().(EnvUnmarshaler).UnmarshalEnv(part); err != nil {
+				return &elementError{index: i, value: part, err: err}
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if suffix, ok := fieldType.Tag.Lookup("suffix"); ok {
+			if !strings.HasSuffix(val, suffix) {
+				return fmt.Errorf("value %q does not end with %q", val, suffix)
+			}
+			val = strings.TrimSpace(strings.TrimSuffix(val, suffix))
+		}
+		val = normalizeNumber(fieldType.Tag, val)
+		if fieldType.Tag.Get("boolint") == "true" {
+			switch strings.ToLower(val) {
+			case "true":
+				val = "1"
+			case "false":
+				val = "0"
+			}
+		}
+	}
+
+	if name, ok := fieldType.Tag.Lookup("enum"); ok && field.Kind() != reflect.Slice {
+		return setEnum(field, name, val)
+	}
+	if ok, err := setStringerEnum(field, val); ok {
+		return err
+	}
+	if name, ok := fieldType.Tag.Lookup("flags"); ok {
+		return p.setFlags(field, fieldType, name, val)
+	}
+	if name, ok := fieldType.Tag.Lookup("variants"); ok {
+		return p.setVariants(field, fieldType, name, val)
+	}
+
+	switch fieldType.Tag.Get("format") {
+	case "decimal":
+		return setDecimal(field, fieldType, val)
+	case "human":
+		if field.Type() != durationType {
+			return fmt.Errorf("human format requires a time.Duration field, got %s", field.Type())
+		}
+		d, err := parseHumanDuration(val)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	case "ms":
+		return p.setMilliseconds(field, fieldType, val)
+	case "pem":
+		return setPrivateKey(field, val)
+	case "uid", "gid":
+		return p.setID(field, fieldType, fieldType.Tag.Get("format"), val)
+	case "relative":
+		if field.Type() != timeType {
+			return fmt.Errorf("relative format requires a time.Time field, got %s", field.Type())
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(val))
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(p.currentTime().Add(d)))
+		return nil
+	case "date", "time":
+		return setDateOrClock(field, fieldType.Tag.Get("format"), val)
+	case "clock":
+		if field.Type() != durationType {
+			return fmt.Errorf("clock format requires a time.Duration field, got %s", field.Type())
+		}
+		d, err := parseClockDuration(val)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	if encoding := fieldType.Tag.Get("encoding"); encoding != "" {
+		decode, ok := lookupCodec(encoding)
+		if !ok {
+			return fmt.Errorf("unknown encoding %q", encoding)
+		}
+		return decode(p, field, fieldType, val)
+	}
+
+	if parse, ok := typeParsers[field.Type()]; ok {
+		v, err := parse(val)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(v))
+		return nil
+	}
+
 	switch field.Interface().(type) {
 	case time.Duration:
 		d, err := time.ParseDuration(val)
@@ -79,33 +762,61 @@ func setValueFromEnv(field reflect.Value, fieldType reflect.StructField, val str
 		field.Set(reflect.ValueOf(d))
 
 	case time.Time:
-		t, err := time.Parse(time.RFC3339, val)
+		t, err := parseTime(val)
 		if err != nil {
 			return err
 		}
 		field.Set(reflect.ValueOf(t))
 
-	case int, int32, int64:
+	case os.FileMode:
+		mode, err := strconv.ParseUint(strings.TrimPrefix(val, "0o"), 8, 32)
+		if err != nil {
+			return err
+		}
+		field.SetUint(mode)
+
+	case int:
 		i, err := strconv.Atoi(val)
 		if err != nil {
 			return err
 		}
 		field.SetInt(int64(i))
 
-	case uint, uint32, uint64:
-		i, err := strconv.ParseUint(val, 10, 64)
+	case int64:
+		i, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+
+	case int8, int16, int32:
+		i, err := strconv.ParseInt(val, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+
+	case uint, uint8, uint16, uint32, uint64:
+		i, err := strconv.ParseUint(val, 10, field.Type().Bits())
 		if err != nil {
 			return err
 		}
 		field.SetUint(i)
 
 	case float32, float64:
-		f, err := strconv.ParseFloat(val, 64)
+		f, err := strconv.ParseFloat(val, field.Type().Bits())
 		if err != nil {
 			return err
 		}
 		field.SetFloat(f)
 
+	case *big.Rat:
+		r, err := parseRat(val)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(r))
+
 	case bool:
 		b, err := strconv.ParseBool(val)
 		if err != nil {
@@ -116,124 +827,504 @@ func setValueFromEnv(field reflect.Value, fieldType reflect.StructField, val str
 	case string:
 		field.SetString(val)
 
-	case []string:
-		field.Set(reflect.ValueOf(strings.Split(val, ",")))
+	default:
+		if s, ok := field.Addr().Interface().(sql.Scanner); ok {
+			return s.Scan(val)
+		}
+		if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(val))
+		}
+		if field.Kind() == reflect.Slice && isListElem(field.Type().Elem()) {
+			return p.setSlice(field, fieldType, val)
+		}
+		if field.Kind() == reflect.Map {
+			return p.setMap(field, val)
+		}
+		if p.strictTypes {
+			return fmt.Errorf("field %s has unsupported type %s", fieldType.Name, field.Type())
+		}
+	}
+	return nil
+}
 
-	case []int:
-		numStrings := strings.Split(val, ",")
-		ints := make([]int, len(numStrings))
-		for i, v := range numStrings {
-			n, err := strconv.Atoi(strings.TrimSpace(v))
-			if err != nil {
-				return err
-			}
-			ints[i] = n
+// isListElem reports whether slices of t are parsed from a separated list,
+// converting each element like a field of type t. Bytes, interfaces and
+// composite elements are left to encodings unless they parse themselves.
+func isListElem(t reflect.Type) bool {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) || reflect.PtrTo(t).Implements(scannerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Uint8, reflect.Interface, reflect.Slice, reflect.Array, reflect.Map:
+		return false
+	case reflect.Struct:
+		return isScalarStruct(t)
+	}
+	return true
+}
+
+// setSlice converts each element of a separated list with the same rules as
+// a field of the slice's element type.
+func (p *Parser) setSlice(field reflect.Value, fieldType reflect.StructField, val string) error {
+	parts := p.splitList(val, fieldType.Tag)
+	slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+	elemType := reflect.StructField{Name: fieldType.Name, Type: field.Type().Elem(), Tag: fieldType.Tag}
+	for i, part := range parts {
+		if elemType.Type.Kind() == reflect.String {
+			// Copy so that elements do not keep the whole value alive.
+			part = string([]byte(part))
+		}
+		if err := p.setValueFromEnv(slice.Index(i), elemType, part); err != nil {
+			return &elementError{index: i, value: part, err: err}
 		}
-		field.Set(reflect.ValueOf(ints))
+	}
+	field.Set(slice)
+	return nil
+}
 
-	case []int32:
-		numStrings := strings.Split(val, ",")
-		ints := make([]int32, len(numStrings))
-		for i, v := range numStrings {
-			n, err := strconv.Atoi(strings.TrimSpace(v))
-			if err != nil {
-				return err
-			}
-			ints[i] = int32(n)
+// setMap decodes comma-separated "key=value" entries into a map, converting
+// keys and values with the same rules as fields of the map's key and value
+// types, e.g. "1=a,2=b" into map[int]string.
+func (p *Parser) setMap(field reflect.Value, val string) error {
+	t := field.Type()
+	m := reflect.MakeMap(t)
+	for _, entry := range strings.Split(val, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("map entry %q is missing '='", entry)
 		}
-		field.Set(reflect.ValueOf(ints))
 
-	case []int64:
-		numStrings := strings.Split(val, ",")
-		ints := make([]int64, len(numStrings))
-		for i, v := range numStrings {
-			n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
-			if err != nil {
-				return err
-			}
-			ints[i] = n
+		key := reflect.New(t.Key()).Elem()
+		if err := p.setValueFromEnv(key, reflect.StructField{Type: t.Key()}, strings.TrimSpace(kv[0])); err != nil {
+			return fmt.Errorf("map entry %q: key: %v", entry, err)
+		}
+		elem := reflect.New(t.Elem()).Elem()
+		if err := p.setValueFromEnv(elem, reflect.StructField{Type: t.Elem()}, strings.TrimSpace(kv[1])); err != nil {
+			return fmt.Errorf("map entry %q: value: %v", entry, err)
 		}
-		field.Set(reflect.ValueOf(ints))
+		m.SetMapIndex(key, elem)
+	}
+	field.Set(m)
+	return nil
+}
 
-	case []float32:
-		numStrings := strings.Split(val, ",")
-		float := make([]float32, len(numStrings))
-		for i, v := range numStrings {
-			n, err := strconv.ParseFloat(strings.TrimSpace(v), 32)
-			if err != nil {
-				return err
-			}
-			float[i] = float32(n)
+// parseRat parses an exact fraction such as "16/9", an integer or a decimal
+// such as "1.25".
+func parseRat(val string) (*big.Rat, error) {
+	val = strings.TrimSpace(val)
+	r, ok := new(big.Rat).SetString(val)
+	if !ok {
+		return nil, fmt.Errorf("invalid rational number %q", val)
+	}
+	return r, nil
+}
+
+// timeLayouts are tried in order when parsing time.Time values.
+var timeLayouts = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseTime parses RFC3339 timestamps with or without fractional seconds,
+// falling back to timestamps without a zone (UTC) and date-only values.
+func parseTime(val string) (time.Time, error) {
+	var firstErr error
+	for _, layout := range timeLayouts {
+		t, err := time.Parse(layout, val)
+		if err == nil {
+			return t, nil
 		}
-		field.Set(reflect.ValueOf(float))
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return time.Time{}, firstErr
+}
 
-	case []float64:
-		numStrings := strings.Split(val, ",")
-		float := make([]float64, len(numStrings))
-		for i, v := range numStrings {
-			n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
-			if err != nil {
-				return err
-			}
-			float[i] = n
+// numberLocales maps a locale tag to its decimal and digit group separators.
+var numberLocales = map[string][2]string{
+	"en": {".", ","},
+	"de": {",", "."},
+	"es": {",", "."},
+	"it": {",", "."},
+	"nl": {",", "."},
+	"pt": {",", "."},
+	"fr": {",", " "},
+	"ch": {".", "'"},
+}
+
+// normalizeNumber rewrites a localized number into the form strconv expects,
+// removing digit group separators and replacing the decimal separator with
+// ".". Separators come from the locale tag and can be overridden with the
+// decimalSep and groupSep tags.
+func normalizeNumber(tag reflect.StructTag, val string) string {
+	seps := numberLocales[tag.Get("locale")]
+	decimalSep, groupSep := seps[0], seps[1]
+	if s, ok := tag.Lookup("decimalSep"); ok {
+		decimalSep = s
+	}
+	if s, ok := tag.Lookup("groupSep"); ok {
+		groupSep = s
+	}
+
+	if groupSep != "" {
+		val = strings.Replace(val, groupSep, "", -1)
+	}
+	if decimalSep != "" && decimalSep != "." {
+		val = strings.Replace(val, decimalSep, ".", -1)
+	}
+	return val
+}
+
+// splitList splits a comma-separated value, or one split by the
+// separatorRegex tag, into its elements and, unless WithTrimSliceElements
+// disabled it, trims the space around each. An empty value has no elements
+// rather than a single empty one. Empty elements such as the middle of "a,,b"
+// are replaced by the elemDefault tag, or with skipEmpty:"true" dropped; with
+// trimming disabled an element of only spaces is not empty.
+func (p *Parser) splitList(val string, tag reflect.StructTag) []string {
+	if val == "" {
+		return []string{}
+	}
+	parts := strings.Split(val, ",")
+	if pattern, ok := tag.Lookup("separatorRegex"); ok {
+		if re, err := separatorRegexp(pattern); err == nil {
+			parts = re.Split(val, -1)
+		}
+	}
+	elemDefault, hasElemDefault := tag.Lookup("elemDefault")
+	for i, part := range parts {
+		if !p.keepSliceSpace {
+			parts[i] = strings.TrimSpace(part)
+		}
+		if parts[i] == "" && hasElemDefault {
+			parts[i] = elemDefault
 		}
-		field.Set(reflect.ValueOf(float))
+	}
+	if tag.Get("skipEmpty") != "true" {
+		return parts
+	}
 
-	case []uint:
-		numStrings := strings.Split(val, ",")
-		unsigned := make([]uint, len(numStrings))
-		for i, v := range numStrings {
-			n, err := strconv.ParseUint(strings.TrimSpace(v), 10, 64)
-			if err != nil {
-				return err
-			}
-			unsigned[i] = uint(n)
+	kept := parts[:0]
+	for _, part := range parts {
+		if part != "" {
+			kept = append(kept, part)
 		}
-		field.Set(reflect.ValueOf(unsigned))
+	}
+	return kept
+}
 
-	case []uint32:
-		numStrings := strings.Split(val, ",")
-		unsigned := make([]uint32, len(numStrings))
-		for i, v := range numStrings {
-			n, err := strconv.ParseUint(strings.TrimSpace(v), 10, 32)
-			if err != nil {
-				return err
+// parseForm parses URL-encoded form data. A sep other than "&" splits the
+// value into pairs first, which allows legacy ";" separated query strings that
+// url.ParseQuery rejects.
+func parseForm(val, sep string) (url.Values, error) {
+	if sep == "" || sep == "&" {
+		return url.ParseQuery(val)
+	}
+
+	values := url.Values{}
+	for _, pair := range strings.Split(val, sep) {
+		if pair == "" {
+			continue
+		}
+		parsed, err := url.ParseQuery(pair)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range parsed {
+			values[k] = append(values[k], v...)
+		}
+	}
+	return values, nil
+}
+
+var separatorCache sync.Map
+
+// separatorRegexp compiles a separatorRegex pattern, caching the result.
+func separatorRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := separatorCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	separatorCache.Store(pattern, re)
+	return re, nil
+}
+
+// setDateOrClock parses a date-only ("2006-01-02") or time-of-day ("15:04" or
+// "15:04:05") value into a time.Time field. Date values are midnight UTC and
+// time values fall on January 1 of year 0 in UTC, as with time.Parse.
+func setDateOrClock(field reflect.Value, format, val string) error {
+	if field.Type() != timeType {
+		return fmt.Errorf("%s format requires a time.Time field, got %s", format, field.Type())
+	}
+	layouts := []string{"2006-01-02"}
+	if format == "time" {
+		layouts = []string{"15:04", "15:04:05"}
+	}
+	val = strings.TrimSpace(val)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, val); err == nil {
+			field.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s %q, expected %s", format, val, strings.Join(layouts, " or "))
+}
+
+// parseClockDuration parses a duration written as a clock reading, either
+// "HH:MM:SS" or "MM:SS", e.g. "01:30:00" as 1h30m.
+func parseClockDuration(val string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(val), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid clock duration %q, expected HH:MM:SS or MM:SS", val)
+	}
+	units := []time.Duration{time.Hour, time.Minute, time.Second}[3-len(parts):]
+	var d time.Duration
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, fmt.Errorf("invalid clock duration %q, expected HH:MM:SS or MM:SS", val)
+		}
+		d += time.Duration(n) * units[i]
+	}
+	return d, nil
+}
+
+// setBinary stores decoded bytes in field, handing them to UnmarshalBinary
+// when the field implements encoding.BinaryUnmarshaler. Fixed-size byte
+// arrays such as [32]byte must receive exactly as many bytes as they hold.
+func setBinary(field reflect.Value, decoded []byte) error {
+	if u, ok := field.Addr().Interface().(encoding.BinaryUnmarshaler); ok {
+		return u.UnmarshalBinary(decoded)
+	}
+	if field.Kind() == reflect.Array && field.Type().Elem().Kind() == reflect.Uint8 {
+		if len(decoded) != field.Len() {
+			return fmt.Errorf("decoded %d bytes, want %d", len(decoded), field.Len())
+		}
+		reflect.Copy(field, reflect.ValueOf(decoded))
+		return nil
+	}
+	if field.Type() != reflect.TypeOf([]byte(nil)) {
+		return fmt.Errorf("cannot store decoded bytes in %s", field.Type())
+	}
+	field.SetBytes(decoded)
+	return nil
+}
+
+// setPairs decodes comma-separated "key:value" entries into a slice of
+// structs, converting the key and value into the element's first and second
+// fields respectively, e.g. "a:3,b:1" into []struct{Name string; Weight int}.
+func (p *Parser) setPairs(field reflect.Value, fieldType reflect.StructField, val string) error {
+	elemType := field.Type()
+	if elemType.Kind() != reflect.Slice || elemType.Elem().Kind() != reflect.Struct || elemType.Elem().NumField() < 2 {
+		return fmt.Errorf("pairs encoding requires a slice of structs with two fields, got %s", field.Type())
+	}
+	elemType = elemType.Elem()
+
+	entries := p.splitList(val, fieldType.Tag)
+	pairs := reflect.MakeSlice(field.Type(), 0, len(entries))
+	for _, entry := range entries {
+		if entry == "" {
+			continue
+		}
+		kv := strings.SplitN(entry, ":", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("pair %q is missing ':'", entry)
+		}
+
+		elem := reflect.New(elemType).Elem()
+		for i, part := range kv {
+			if !elem.Field(i).CanSet() {
+				return fmt.Errorf("field '%s' of %s is not settable", elemType.Field(i).Name, elemType)
+			}
+			if !p.keepSliceSpace {
+				part = strings.TrimSpace(part)
+			}
+			if err := p.setValueFromEnv(elem.Field(i), elemType.Field(i), part); err != nil {
+				return fmt.Errorf("pair %q: %v", entry, err)
 			}
-			unsigned[i] = uint32(n)
 		}
-		field.Set(reflect.ValueOf(unsigned))
+		pairs = reflect.Append(pairs, elem)
+	}
+	field.Set(pairs)
+	return nil
+}
 
-	case []uint64:
-		numStrings := strings.Split(val, ",")
-		unsigned := make([]uint64, len(numStrings))
-		for i, v := range numStrings {
-			n, err := strconv.ParseUint(strings.TrimSpace(v), 10, 64)
-			if err != nil {
-				return err
+// setKV decodes "key=value" entries separated by ";" (or the kvsep tag) into
+// a struct whose fields are matched by their kv tag, e.g. "host=db;port=5432"
+// into struct{ Host string `kv:"host"`; Port int `kv:"port"` }.
+func (p *Parser) setKV(field reflect.Value, fieldType reflect.StructField, val string) error {
+	t := field.Type()
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("kv encoding requires a struct field, got %s", t)
+	}
+
+	fields := map[string]int{}
+	for i := 0; i < t.NumField(); i++ {
+		if name := t.Field(i).Tag.Get("kv"); name != "" && name != "-" && field.Field(i).CanSet() {
+			fields[name] = i
+		}
+	}
+
+	sep := fieldType.Tag.Get("kvsep")
+	if sep == "" {
+		sep = ";"
+	}
+	for _, entry := range strings.Split(val, sep) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("entry %q is missing '='", entry)
+		}
+		key := strings.TrimSpace(kv[0])
+		i, ok := fields[key]
+		if !ok {
+			if p.ignoreUnknownKV {
+				continue
 			}
-			unsigned[i] = uint64(n)
+			return fmt.Errorf("unknown key %q", key)
 		}
-		field.Set(reflect.ValueOf(unsigned))
+		if err := p.setValueFromEnv(field.Field(i), t.Field(i), strings.TrimSpace(kv[1])); err != nil {
+			return fmt.Errorf("key %q: %v", key, err)
+		}
+	}
+	return nil
+}
 
+// setDecimal parses a fixed-precision decimal such as "12.34" into an integer
+// field scaled by 10^scale, so "12.34" with scale:"2" is stored as 1234.
+func setDecimal(field reflect.Value, fieldType reflect.StructField, val string) error {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 	default:
-		switch fieldType.Tag.Get("encoding") {
-		case "json":
-			return json.Unmarshal([]byte(val), field.Addr().Interface())
-		case "xml":
-			return xml.Unmarshal([]byte(val), field.Addr().Interface())
-		case "form":
-			parsed, err := url.ParseQuery(val)
-			if err != nil {
-				return err
-			}
-			field.Set(reflect.ValueOf(parsed)) // if field is url.Values
-		case "base64":
-			decoded, err := base64.StdEncoding.DecodeString(val)
+		return fmt.Errorf("decimal format requires an integer field, got %s", field.Type())
+	}
+
+	scale := 0
+	if s := fieldType.Tag.Get("scale"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid scale %q", s)
+		}
+		scale = n
+	}
+
+	n, err := parseDecimal(val, scale)
+	if err != nil {
+		return err
+	}
+	if field.OverflowInt(n) {
+		return fmt.Errorf("decimal %q overflows %s", val, field.Type())
+	}
+	field.SetInt(n)
+	return nil
+}
+
+func parseDecimal(val string, scale int) (int64, error) {
+	s := strings.TrimSpace(val)
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("invalid decimal %q", val)
+	}
+	if len(fracPart) > scale {
+		return 0, fmt.Errorf("decimal %q has more than %d fractional digits", val, scale)
+	}
+
+	digits := intPart + fracPart + strings.Repeat("0", scale-len(fracPart))
+	return strconv.ParseInt(sign+digits, 10, 64)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// setMilliseconds parses integer milliseconds into a time.Duration or a
+// comma-separated list of them into a []time.Duration.
+func (p *Parser) setMilliseconds(field reflect.Value, fieldType reflect.StructField, val string) error {
+	switch field.Type() {
+	case durationType:
+		n, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n * int64(time.Millisecond))
+
+	case reflect.TypeOf([]time.Duration(nil)):
+		numStrings := p.splitList(val, fieldType.Tag)
+		durations := make([]time.Duration, len(numStrings))
+		for i, v := range numStrings {
+			n, err := strconv.ParseInt(v, 10, 64)
 			if err != nil {
-				return err
+				return &elementError{index: i, value: v, err: err}
 			}
-			field.Set(reflect.ValueOf(decoded)) // if field is []byte
+			durations[i] = time.Duration(n) * time.Millisecond
 		}
+		field.Set(reflect.ValueOf(durations))
+
+	default:
+		return fmt.Errorf("ms format requires a time.Duration or []time.Duration field, got %s", field.Type())
 	}
 	return nil
 }
+
+var humanDurationUnits = map[string]time.Duration{
+	"day": 24 * time.Hour, "days": 24 * time.Hour,
+	"hour": time.Hour, "hours": time.Hour,
+	"minute": time.Minute, "minutes": time.Minute,
+	"second": time.Second, "seconds": time.Second,
+	"millisecond": time.Millisecond, "milliseconds": time.Millisecond,
+}
+
+// parseHumanDuration parses durations written out in words, such as
+// "1 hour 30 minutes" or "2 days".
+func parseHumanDuration(val string) (time.Duration, error) {
+	fields := strings.Fields(strings.ToLower(strings.Replace(val, ",", " ", -1)))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("invalid duration %q", val)
+	}
+
+	var total time.Duration
+	for i := 0; i < len(fields); i++ {
+		if fields[i] == "and" {
+			continue
+		}
+		if i+1 >= len(fields) {
+			return 0, fmt.Errorf("invalid duration %q", val)
+		}
+		n, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", val)
+		}
+		unit, ok := humanDurationUnits[fields[i+1]]
+		if !ok {
+			return 0, fmt.Errorf("unknown duration unit %q", fields[i+1])
+		}
+		total += time.Duration(n * float64(unit))
+		i++
+	}
+	return total, nil
+}
@@ -0,0 +1,33 @@
+//go:build go1.20
+// +build go1.20
+
+package envparser
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_StringSlice_CopiesElements(t *testing.T) {
+	src := "alpha, beta,,gamma"
+	type Env struct {
+		Names   []string `env:"NAMES"`
+		Compact []string `env:"NAMES" skipEmpty:"true"`
+	}
+	var env Env
+	err := Parse(&env, WithSource(MapSource{"NAMES": src}))
+	assert.NoError(t, err)
+	assert.Equal(t, env.Names, []string{"alpha", "beta", "", "gamma"})
+	assert.Equal(t, env.Compact, []string{"alpha", "beta", "gamma"})
+
+	start := uintptr(unsafe.Pointer(unsafe.StringData(src)))
+	end := start + uintptr(len(src))
+	for _, list := range [][]string{env.Names, env.Compact} {
+		for i, name := range list {
+			p := uintptr(unsafe.Pointer(unsafe.StringData(name)))
+			assert.False(t, p >= start && p < end, "element %d shares memory with the source value", i)
+		}
+	}
+}
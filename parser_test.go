@@ -1,7 +1,16 @@
 package envparser
 
 import (
+	"database/sql"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
 	"net/url"
+	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -18,13 +27,78 @@ func TestParse_NonPointer_Error(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParseValue(t *testing.T) {
+	t.Setenv("STRING_VAL", "hello")
+	type Env struct {
+		StringVal string `env:"STRING_VAL"`
+	}
+	var env Env
+	err := ParseValue(reflect.ValueOf(&env).Elem())
+	assert.NoError(t, err)
+	assert.Equal(t, env.StringVal, "hello")
+}
+
+func TestParseValue_NotAddressable_Error(t *testing.T) {
+	t.Setenv("STRING_VAL", "hello")
+	type Env struct {
+		StringVal string `env:"STRING_VAL"`
+	}
+	var env Env
+	err := ParseValue(reflect.ValueOf(env))
+	assert.Error(t, err)
+}
+
+func TestParse_NilPointer_Error(t *testing.T) {
+	type Env struct {
+		StringVal string `env:"STRING_VAL"`
+	}
+	err := Parse((*Env)(nil))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "non-nil pointer")
+}
+
+func TestParse_Nil_Error(t *testing.T) {
+	err := Parse(nil)
+	assert.Error(t, err)
+}
+
+func TestParse_WithAllocate(t *testing.T) {
+	t.Setenv("STRING_VAL", "hello")
+	type Env struct {
+		StringVal string `env:"STRING_VAL"`
+	}
+	var env *Env
+	err := Parse(&env, WithAllocate())
+	assert.NoError(t, err)
+	assert.Equal(t, env.StringVal, "hello")
+}
+
 func TestParse_MissingENV_Error(t *testing.T) {
 	type Env struct {
 		StringVal string `env:"STRING_VAL"`
+		IntVal    int    `env:"INT_VAL"`
+		BoolVal   bool   `env:"BOOL_VAL"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "error parsing environment to struct:\n"+
+		"missing required environment variables: STRING_VAL, INT_VAL, BOOL_VAL\n")
+}
+
+func TestParse_MissingENV_PartialStruct(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("HOST", "localhost")
+	type Env struct {
+		Token string `env:"TOKEN"`
+		Port  int    `env:"PORT"`
+		Level int    `env:"LEVEL"`
+		Host  string `env:"HOST"`
 	}
 	var env Env
 	err := Parse(&env)
 	assert.Error(t, err)
+	assert.Equal(t, env, Env{Port: 8080, Host: "localhost"})
 }
 
 func TestParse_Ignored(t *testing.T) {
@@ -71,6 +145,59 @@ func TestParse_Bool_Error(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParse_Bool_Presence(t *testing.T) {
+	t.Setenv("VERBOSE", "")
+	t.Setenv("TRACE", "no")
+	t.Setenv("QUIET", "")
+	os.Unsetenv("QUIET")
+	type Env struct {
+		Verbose bool `env:"VERBOSE" presence:"true"`
+		Trace   bool `env:"TRACE" presence:"true"`
+		Quiet   bool `env:"QUIET" presence:"true"`
+	}
+	env := Env{Quiet: true}
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.True(t, env.Verbose)
+	assert.True(t, env.Trace)
+	assert.False(t, env.Quiet)
+}
+
+func TestParse_Bool_Negatable(t *testing.T) {
+	type Env struct {
+		Feature bool `env:"FEATURE" default:"true" negatable:"true"`
+	}
+	for _, tc := range []struct {
+		name    string
+		vars    map[string]string
+		feature bool
+	}{
+		{name: "unset", vars: map[string]string{}, feature: true},
+		{name: "explicit false", vars: map[string]string{"FEATURE": "false"}, feature: false},
+		{name: "negation key", vars: map[string]string{"NO_FEATURE": "1"}, feature: false},
+		{name: "negation key false", vars: map[string]string{"NO_FEATURE": "false"}, feature: true},
+		{name: "negation wins", vars: map[string]string{"FEATURE": "true", "NO_FEATURE": "true"}, feature: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var env Env
+			err := New(WithMapEnviron(tc.vars)).Parse(&env)
+			assert.NoError(t, err)
+			assert.Equal(t, env.Feature, tc.feature)
+		})
+	}
+}
+
+func TestParse_Bool_Negatable_Error(t *testing.T) {
+	t.Setenv("NO_FEATURE", "maybe")
+	type Env struct {
+		Feature bool `env:"FEATURE" default:"true" negatable:"true"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "NO_FEATURE")
+}
+
 func TestParse_Int(t *testing.T) {
 	t.Setenv("INT_VAL", "2")
 	type Env struct {
@@ -92,6 +219,71 @@ func TestParse_Int_Error(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParse_Int32_Overflow_Error(t *testing.T) {
+	t.Setenv("INT32_VAL", "4000000000")
+	type Env struct {
+		Int32Val int32 `env:"INT32_VAL"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `strconv.ParseInt: parsing "4000000000": value out of range`)
+	assert.Equal(t, env.Int32Val, int32(0))
+}
+
+func TestParse_Int64(t *testing.T) {
+	t.Setenv("INT64_VAL", "9000000000")
+	type Env struct {
+		Int64Val int64 `env:"INT64_VAL"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Int64Val, int64(9000000000))
+}
+
+func TestParse_Int64_Overflow_Error(t *testing.T) {
+	t.Setenv("INT64_VAL", "9223372036854775808")
+	type Env struct {
+		Int64Val int64 `env:"INT64_VAL"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `strconv.ParseInt: parsing "9223372036854775808": value out of range`)
+}
+
+func TestParse_Int8Int16(t *testing.T) {
+	t.Setenv("INT8_VAL", "-128")
+	t.Setenv("INT16_VAL", "32767")
+	t.Setenv("INT8_LIST", "1,-2")
+	type Env struct {
+		Int8Val  int8   `env:"INT8_VAL"`
+		Int16Val int16  `env:"INT16_VAL"`
+		Int8List []int8 `env:"INT8_LIST"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Int8Val, int8(-128))
+	assert.Equal(t, env.Int16Val, int16(32767))
+	assert.Equal(t, env.Int8List, []int8{1, -2})
+}
+
+func TestParse_Int8Int16_Error(t *testing.T) {
+	t.Setenv("INT8_VAL", "300")
+	t.Setenv("INT16_VAL", "40000")
+	type Env struct {
+		Int8Val  int8  `env:"INT8_VAL"`
+		Int16Val int16 `env:"INT16_VAL"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `env 'INT8_VAL': strconv.ParseInt: parsing "300": value out of range`)
+	assert.Contains(t, err.Error(), `env 'INT16_VAL': strconv.ParseInt: parsing "40000": value out of range`)
+}
+
 func TestParse_Float(t *testing.T) {
 	t.Setenv("FLOAT_VAL", "3.14")
 	type Env struct {
@@ -134,6 +326,37 @@ func TestParse_Uint_Error(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParse_Uint8Uint16(t *testing.T) {
+	t.Setenv("UINT8_VAL", "255")
+	t.Setenv("UINT16_VAL", "65535")
+	t.Setenv("UINT16_LIST", "1,2")
+	type Env struct {
+		Uint8Val   uint8    `env:"UINT8_VAL"`
+		Uint16Val  uint16   `env:"UINT16_VAL"`
+		Uint16List []uint16 `env:"UINT16_LIST"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Uint8Val, uint8(255))
+	assert.Equal(t, env.Uint16Val, uint16(65535))
+	assert.Equal(t, env.Uint16List, []uint16{1, 2})
+}
+
+func TestParse_Uint8Uint16_Error(t *testing.T) {
+	t.Setenv("UINT8_VAL", "256")
+	t.Setenv("UINT16_VAL", "65536")
+	type Env struct {
+		Uint8Val  uint8  `env:"UINT8_VAL"`
+		Uint16Val uint16 `env:"UINT16_VAL"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `env 'UINT8_VAL': strconv.ParseUint: parsing "256": value out of range`)
+	assert.Contains(t, err.Error(), `env 'UINT16_VAL': strconv.ParseUint: parsing "65536": value out of range`)
+}
+
 func TestParse_Duration(t *testing.T) {
 	t.Setenv("DURATION_VAL", "2h30m")
 	type Env struct {
@@ -168,6 +391,23 @@ func TestParse_Datetime(t *testing.T) {
 	assert.Equal(t, env.DateTimeVal, expectedTime)
 }
 
+func TestParse_Datetime_Formats(t *testing.T) {
+	t.Setenv("SECONDS", "2023-10-01T15:04:05Z")
+	t.Setenv("NANOS", "2023-10-01T15:04:05.123456789+02:00")
+	t.Setenv("DATE_ONLY", "2023-10-01")
+	type Env struct {
+		Seconds  time.Time `env:"SECONDS"`
+		Nanos    time.Time `env:"NANOS"`
+		DateOnly time.Time `env:"DATE_ONLY"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.True(t, env.Seconds.Equal(time.Date(2023, 10, 1, 15, 4, 5, 0, time.UTC)))
+	assert.True(t, env.Nanos.Equal(time.Date(2023, 10, 1, 13, 4, 5, 123456789, time.UTC)))
+	assert.True(t, env.DateOnly.Equal(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)))
+}
+
 func TestParse_Datetime_Error(t *testing.T) {
 	t.Setenv("DATETIME_VAL", "29-01-2024 15:00:00")
 	type Env struct {
@@ -189,6 +429,60 @@ func TestParse_StringSlice(t *testing.T) {
 	assert.Equal(t, env.StringSliceVal, []string{"a", "b", "c"})
 }
 
+func TestParse_StringSlice_SkipEmpty(t *testing.T) {
+	t.Setenv("STRING_SLICE", "a,,b,")
+	t.Setenv("INT_SLICE", "1,,2")
+	type Env struct {
+		StringSliceVal []string `env:"STRING_SLICE" skipEmpty:"true"`
+		IntSliceVal    []int    `env:"INT_SLICE" skipEmpty:"true"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.StringSliceVal, []string{"a", "b"})
+	assert.Equal(t, len(env.StringSliceVal), cap(env.StringSliceVal))
+	assert.Equal(t, env.IntSliceVal, []int{1, 2})
+}
+
+func TestParse_StringSlice_IndependentBacking(t *testing.T) {
+	t.Setenv("STRING_SLICE", "a,b,c")
+	type Env struct {
+		StringSliceVal []string `env:"STRING_SLICE"`
+	}
+	var first, second Env
+	assert.NoError(t, Parse(&first))
+	assert.NoError(t, Parse(&second))
+
+	first.StringSliceVal[0] = "changed"
+	first.StringSliceVal = append(first.StringSliceVal, "d")
+	assert.Equal(t, second.StringSliceVal, []string{"a", "b", "c"})
+	assert.Equal(t, len(second.StringSliceVal), cap(second.StringSliceVal))
+}
+
+func TestParse_SeparatorRegex(t *testing.T) {
+	t.Setenv("STRING_SLICE", "a, b;c  d")
+	t.Setenv("INT_SLICE", "1;2 3")
+	type Env struct {
+		StringSliceVal []string `env:"STRING_SLICE" separatorRegex:"[,;\\s]+"`
+		IntSliceVal    []int    `env:"INT_SLICE" separatorRegex:"[,;\\s]+"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.StringSliceVal, []string{"a", "b", "c", "d"})
+	assert.Equal(t, env.IntSliceVal, []int{1, 2, 3})
+}
+
+func TestParse_SeparatorRegex_Error(t *testing.T) {
+	t.Setenv("STRING_SLICE", "a,b")
+	type Env struct {
+		StringSliceVal []string `env:"STRING_SLICE" separatorRegex:"[,"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
 func TestParse_IntSlice(t *testing.T) {
 	t.Setenv("INT_SLICE", "1,2,3")
 	type Env struct {
@@ -398,6 +692,46 @@ func TestParse_Embeded_Error(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParse_InlineStruct(t *testing.T) {
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_PORT", "5432")
+	type Env struct {
+		DB struct {
+			Host string `env:"DB_HOST"`
+			Port int    `env:"DB_PORT"`
+		}
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.DB.Host, "localhost")
+	assert.Equal(t, env.DB.Port, 5432)
+}
+
+func TestParse_InlineStruct_Prefix(t *testing.T) {
+	t.Setenv("PRIMARY_HOST", "primary")
+	t.Setenv("REPLICA_HOST", "replica")
+	t.Setenv("REPLICA_POOL_SIZE", "4")
+	type Pool struct {
+		Size int `env:"SIZE"`
+	}
+	type Env struct {
+		Primary struct {
+			Host string `env:"HOST"`
+		} `prefix:"PRIMARY_"`
+		Replica struct {
+			Host string `env:"HOST"`
+			Pool Pool   `prefix:"POOL_"`
+		} `prefix:"REPLICA_"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Primary.Host, "primary")
+	assert.Equal(t, env.Replica.Host, "replica")
+	assert.Equal(t, env.Replica.Pool.Size, 4)
+}
+
 func TestParse_Encoding_JSON(t *testing.T) {
 	t.Setenv("JSON_VAL", `{"field":"jsonvalue"}`)
 	type JSONStruct struct {
@@ -425,6 +759,38 @@ func TestParse_Encoding_JSON_Error(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParse_Encoding_JSON_StructSlice(t *testing.T) {
+	t.Setenv("SERVERS", `[{"host":"a","port":80},{"host":"b"}]`)
+	t.Setenv("TAGS", `["x,y","z"]`)
+	type Server struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+	type Env struct {
+		Servers []Server `env:"SERVERS" encoding:"json"`
+		Tags    []string `env:"TAGS" encoding:"json"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Servers, []Server{{Host: "a", Port: 80}, {Host: "b"}})
+	assert.Equal(t, env.Tags, []string{"x,y", "z"})
+}
+
+func TestParse_Encoding_JSON_StructSlice_Error(t *testing.T) {
+	t.Setenv("SERVERS", `[{"host":"a"},{"host":]`)
+	type Server struct {
+		Host string `json:"host"`
+	}
+	type Env struct {
+		Servers []Server `env:"SERVERS" encoding:"json"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "env 'SERVERS'")
+}
+
 func TestParse_Encoding_XML(t *testing.T) {
 	t.Setenv("XML_VAL", `<XMLStruct><field>xmlvalue</field></XMLStruct>`)
 	type XMLStruct struct {
@@ -474,6 +840,18 @@ func TestParse_Encoding_Form_Error(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParse_Encoding_Form_Semicolon(t *testing.T) {
+	t.Setenv("FORM_DATA", "field1=value;field2=val;field2=other")
+	type Env struct {
+		FormVal url.Values `env:"FORM_DATA" encoding:"form" formsep:";"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.FormVal.Get("field1"), "value")
+	assert.Equal(t, env.FormVal["field2"], []string{"val", "other"})
+}
+
 func TestParse_Encoding_Base64(t *testing.T) {
 	t.Setenv("BASE64_DATA", `aGVsbG8gd29ybGQ=`)
 	type Env struct {
@@ -519,3 +897,1013 @@ func TestParse_Unexported_Member(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, u.dataUnexported, "")
 }
+
+func TestParse_Decimal(t *testing.T) {
+	t.Setenv("PRICE", "12.34")
+	t.Setenv("FEE", "-0.5")
+	type Env struct {
+		Price int64 `env:"PRICE" format:"decimal" scale:"2"`
+		Fee   int64 `env:"FEE" format:"decimal" scale:"2"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Price, int64(1234))
+	assert.Equal(t, env.Fee, int64(-50))
+}
+
+func TestParse_Decimal_TooManyDigits_Error(t *testing.T) {
+	t.Setenv("PRICE", "12.345")
+	type Env struct {
+		Price int64 `env:"PRICE" format:"decimal" scale:"2"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_Decimal_Error(t *testing.T) {
+	t.Setenv("PRICE", "12.3a")
+	type Env struct {
+		Price int64 `env:"PRICE" format:"decimal" scale:"2"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_Default(t *testing.T) {
+	type Env struct {
+		Port int `env:"PORT" default:"8080"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 8080)
+
+	t.Setenv("PORT", "9090")
+	err = Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 9090)
+}
+
+func TestParse_Default_Error(t *testing.T) {
+	type Env struct {
+		Port int `env:"PORT" default:"abc"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "PORT")
+}
+
+func TestParse_Default_Ignored(t *testing.T) {
+	type Env struct {
+		Port int `env:"-" default:"8080"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 0)
+}
+
+func TestParse_Optional(t *testing.T) {
+	t.Setenv("HOST", "localhost")
+	type Env struct {
+		Name    string `env:"NAME,optional"`
+		Retries int    `env:"RETRIES" optional:"true"`
+		Port    int    `env:"PORT,optional" default:"8080"`
+		Host    string `env:"HOST,optional"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env, Env{Port: 8080, Host: "localhost"})
+}
+
+func TestParse_Optional_Error(t *testing.T) {
+	t.Setenv("RETRIES", "many")
+	type Env struct {
+		Retries int `env:"RETRIES,optional"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "RETRIES")
+}
+
+func TestParse_FallbackOnError(t *testing.T) {
+	t.Setenv("PORT", "not a port")
+	type Env struct {
+		Port int `env:"PORT" default:"8080" fallbackOnError:"true"`
+	}
+	var env Env
+	p := New()
+	err := p.Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 8080)
+	assert.Len(t, p.Warnings(), 1)
+	assert.Contains(t, p.Warnings()[0], "PORT")
+}
+
+func TestParse_FallbackOnError_NoDefault_Error(t *testing.T) {
+	t.Setenv("PORT", "not a port")
+	type Env struct {
+		Port int `env:"PORT" fallbackOnError:"true"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_HumanDuration(t *testing.T) {
+	t.Setenv("TIMEOUT", "1 hour 30 minutes")
+	t.Setenv("RETENTION", "2 days")
+	type Env struct {
+		Timeout   time.Duration `env:"TIMEOUT" format:"human"`
+		Retention time.Duration `env:"RETENTION" format:"human"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Timeout, time.Hour+30*time.Minute)
+	assert.Equal(t, env.Retention, 48*time.Hour)
+}
+
+func TestParse_HumanDuration_Error(t *testing.T) {
+	t.Setenv("TIMEOUT", "a little while")
+	type Env struct {
+		Timeout time.Duration `env:"TIMEOUT" format:"human"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_DurationSlice(t *testing.T) {
+	t.Setenv("BACKOFF", "1s, 5s,1m")
+	type Env struct {
+		Backoff []time.Duration `env:"BACKOFF"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Backoff, []time.Duration{time.Second, 5 * time.Second, time.Minute})
+}
+
+func TestParse_DurationSlice_Milliseconds(t *testing.T) {
+	t.Setenv("INTERVALS_MS", "100,250,1000")
+	type Env struct {
+		Intervals []time.Duration `env:"INTERVALS_MS" format:"ms"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Intervals, []time.Duration{100 * time.Millisecond, 250 * time.Millisecond, time.Second})
+}
+
+func TestParse_DurationSlice_Milliseconds_Error(t *testing.T) {
+	t.Setenv("INTERVALS_MS", "100,2.5s,1000")
+	type Env struct {
+		Intervals []time.Duration `env:"INTERVALS_MS" format:"ms"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_Encoding_Hex(t *testing.T) {
+	t.Setenv("HEX_DATA", "68656c6c6f")
+	type Env struct {
+		Data []byte `env:"HEX_DATA" encoding:"hex"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, string(env.Data), "hello")
+}
+
+func TestParse_Encoding_Hex_Error(t *testing.T) {
+	t.Setenv("HEX_DATA", "zz")
+	type Env struct {
+		Data []byte `env:"HEX_DATA" encoding:"hex"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_Encoding_ByteArray(t *testing.T) {
+	t.Setenv("HEX_KEY", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	t.Setenv("BASE64_KEY", "AQIDBA==")
+	type Env struct {
+		HexKey    [32]byte `env:"HEX_KEY" encoding:"hex"`
+		Base64Key [4]byte  `env:"BASE64_KEY" encoding:"base64"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	for i, b := range env.HexKey {
+		assert.Equal(t, b, byte(i))
+	}
+	assert.Equal(t, env.Base64Key, [4]byte{1, 2, 3, 4})
+}
+
+func TestParse_Encoding_ByteArray_Error(t *testing.T) {
+	t.Setenv("HEX_KEY", "0a0b0c")
+	type Env struct {
+		HexKey [32]byte `env:"HEX_KEY" encoding:"hex"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "decoded 3 bytes, want 32")
+}
+
+type binaryID [4]byte
+
+func (id *binaryID) UnmarshalBinary(data []byte) error {
+	if len(data) != len(id) {
+		return errors.New("binaryID must be 4 bytes")
+	}
+	copy(id[:], data)
+	return nil
+}
+
+func TestParse_Encoding_BinaryUnmarshaler(t *testing.T) {
+	t.Setenv("BASE64_ID", "AQIDBA==")
+	t.Setenv("HEX_ID", "0a0b0c0d")
+	type Env struct {
+		Base64ID binaryID `env:"BASE64_ID" encoding:"base64"`
+		HexID    binaryID `env:"HEX_ID" encoding:"hex"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Base64ID, binaryID{1, 2, 3, 4})
+	assert.Equal(t, env.HexID, binaryID{10, 11, 12, 13})
+}
+
+func TestParse_Encoding_BinaryUnmarshaler_Error(t *testing.T) {
+	t.Setenv("HEX_ID", "0a0b")
+	type Env struct {
+		HexID binaryID `env:"HEX_ID" encoding:"hex"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_Encoding_Pairs(t *testing.T) {
+	t.Setenv("BACKENDS", "a:3, b:1")
+	type Backend struct {
+		Name   string
+		Weight int
+	}
+	type Env struct {
+		Backends []Backend `env:"BACKENDS" encoding:"pairs"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Backends, []Backend{{Name: "a", Weight: 3}, {Name: "b", Weight: 1}})
+}
+
+func TestParse_Encoding_Pairs_Error(t *testing.T) {
+	t.Setenv("BACKENDS", "a:3,b:heavy")
+	type Backend struct {
+		Name   string
+		Weight int
+	}
+	type Env struct {
+		Backends []Backend `env:"BACKENDS" encoding:"pairs"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_EmptySlice(t *testing.T) {
+	t.Setenv("TAGS", "")
+	t.Setenv("IDS", "")
+	type Env struct {
+		Tags []string `env:"TAGS"`
+		IDs  []int    `env:"IDS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.NotNil(t, env.Tags)
+	assert.Len(t, env.Tags, 0)
+	assert.NotNil(t, env.IDs)
+	assert.Len(t, env.IDs, 0)
+}
+
+func TestParse_LocalizedNumbers(t *testing.T) {
+	t.Setenv("RATE", "1,5")
+	t.Setenv("COUNT", "1.000")
+	t.Setenv("TOTAL", "1 234,75")
+	t.Setenv("LIMIT", "10,000")
+	type Env struct {
+		Rate  float64 `env:"RATE" decimalSep:","`
+		Count int     `env:"COUNT" locale:"de"`
+		Total float64 `env:"TOTAL" locale:"fr"`
+		Limit uint    `env:"LIMIT" groupSep:","`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Rate, 1.5)
+	assert.Equal(t, env.Count, 1000)
+	assert.Equal(t, env.Total, 1234.75)
+	assert.Equal(t, env.Limit, uint(10000))
+}
+
+func TestParse_LocalizedNumbers_Error(t *testing.T) {
+	t.Setenv("RATE", "1,5")
+	type Env struct {
+		Rate float64 `env:"RATE"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_Deprecated(t *testing.T) {
+	t.Setenv("OLD_KEY", "value")
+	type Env struct {
+		Old string `env:"OLD_KEY" deprecated:"use NEW_KEY instead"`
+	}
+	var env Env
+	p := New()
+	err := p.Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Old, "value")
+	assert.Equal(t, p.Warnings(), []string{"env 'OLD_KEY' is deprecated: use NEW_KEY instead"})
+}
+
+func TestParse_Deprecated_Unset(t *testing.T) {
+	type Env struct {
+		Old string `env:"OLD_KEY" default:"fallback" deprecated:"use NEW_KEY instead"`
+	}
+	var env Env
+	p := New()
+	err := p.Parse(&env)
+	assert.NoError(t, err)
+	assert.Empty(t, p.Warnings())
+}
+
+func TestParse_FileMode(t *testing.T) {
+	t.Setenv("UMASK", "0644")
+	t.Setenv("DIR_MODE", "755")
+	type Env struct {
+		Umask   os.FileMode `env:"UMASK"`
+		DirMode os.FileMode `env:"DIR_MODE"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Umask, os.FileMode(0644))
+	assert.Equal(t, env.DirMode, os.FileMode(0755))
+}
+
+func TestParse_FileMode_Error(t *testing.T) {
+	t.Setenv("UMASK", "0699")
+	type Env struct {
+		Umask os.FileMode `env:"UMASK"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+type jsonFallbackDB struct {
+	Host string `env:"DB_HOST" json:"host"`
+	Port int    `env:"DB_PORT" json:"port"`
+}
+
+func TestParse_JSONFallback_Scalars(t *testing.T) {
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_PORT", "5432")
+	t.Setenv("DB_JSON", `{"host":"remote","port":6543}`)
+	type Env struct {
+		DB jsonFallbackDB `jsonFallback:"DB_JSON"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.DB, jsonFallbackDB{Host: "localhost", Port: 5432})
+}
+
+func TestParse_JSONFallback_JSON(t *testing.T) {
+	t.Setenv("DB_JSON", `{"host":"remote","port":6543}`)
+	type Env struct {
+		DB jsonFallbackDB `jsonFallback:"DB_JSON"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.DB, jsonFallbackDB{Host: "remote", Port: 6543})
+}
+
+func TestParse_JSONFallback_Missing_Error(t *testing.T) {
+	type Env struct {
+		DB jsonFallbackDB `jsonFallback:"DB_JSON"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+type defaulterEnv struct {
+	Host string `env:"HOST"`
+	Port int    `env:"PORT"`
+}
+
+func (e *defaulterEnv) SetDefaults() {
+	e.Host = "localhost"
+	e.Port = 8080
+}
+
+func TestParse_Defaulter(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	var env defaulterEnv
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Host, "localhost")
+	assert.Equal(t, env.Port, 9090)
+}
+
+func TestParse_Defaulter_Nested(t *testing.T) {
+	t.Setenv("HOST", "db")
+	type Env struct {
+		DB defaulterEnv
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.DB, defaulterEnv{Host: "db", Port: 8080})
+}
+
+type asn1Cert struct {
+	Serial  int
+	Subject string
+}
+
+func TestParse_Encoding_ASN1(t *testing.T) {
+	der, err := asn1.Marshal(asn1Cert{Serial: 42, Subject: "example"})
+	assert.NoError(t, err)
+	t.Setenv("CERT", base64.StdEncoding.EncodeToString(der))
+	type Env struct {
+		Cert asn1Cert `env:"CERT" encoding:"asn1"`
+	}
+	var env Env
+	err = Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Cert, asn1Cert{Serial: 42, Subject: "example"})
+}
+
+func TestParse_Encoding_ASN1_Error(t *testing.T) {
+	t.Setenv("CERT", base64.StdEncoding.EncodeToString([]byte{0x30, 0x05, 0x02}))
+	type Env struct {
+		Cert asn1Cert `env:"CERT" encoding:"asn1"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+type kvDatabase struct {
+	Host    string        `kv:"host"`
+	Port    int           `kv:"port"`
+	Timeout time.Duration `kv:"timeout"`
+}
+
+func TestParse_Encoding_KV(t *testing.T) {
+	t.Setenv("DB", "host=db; port=5432;timeout=5s")
+	t.Setenv("CACHE", "host=cache,port=6379")
+	type Env struct {
+		DB    kvDatabase `env:"DB" encoding:"kv"`
+		Cache kvDatabase `env:"CACHE" encoding:"kv" kvsep:","`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.DB, kvDatabase{Host: "db", Port: 5432, Timeout: 5 * time.Second})
+	assert.Equal(t, env.Cache, kvDatabase{Host: "cache", Port: 6379})
+}
+
+func TestParse_Encoding_KV_UnknownKey_Error(t *testing.T) {
+	t.Setenv("DB", "host=db;user=admin")
+	type Env struct {
+		DB kvDatabase `env:"DB" encoding:"kv"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_Encoding_KV_WithIgnoreUnknownKV(t *testing.T) {
+	t.Setenv("DB", "host=db;user=admin")
+	type Env struct {
+		DB kvDatabase `env:"DB" encoding:"kv"`
+	}
+	var env Env
+	err := Parse(&env, WithIgnoreUnknownKV())
+	assert.NoError(t, err)
+	assert.Equal(t, env.DB, kvDatabase{Host: "db"})
+}
+
+func TestParse_Map(t *testing.T) {
+	t.Setenv("LABELS", "team=core, tier=1")
+	t.Setenv("SCORES", "1=a,2=b")
+	t.Setenv("LIMITS", "cpu=1.5,mem=512")
+	type Env struct {
+		Labels map[string]string  `env:"LABELS"`
+		Scores map[int]string     `env:"SCORES"`
+		Limits map[string]float64 `env:"LIMITS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Labels, map[string]string{"team": "core", "tier": "1"})
+	assert.Equal(t, env.Scores, map[int]string{1: "a", 2: "b"})
+	assert.Equal(t, env.Limits, map[string]float64{"cpu": 1.5, "mem": 512})
+}
+
+func TestParse_Map_KeyError(t *testing.T) {
+	t.Setenv("SCORES", "1=a,two=b")
+	type Env struct {
+		Scores map[int]string `env:"SCORES"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `"two=b"`)
+}
+
+type testColor string
+
+func (c *testColor) UnmarshalEnv(value string) error {
+	switch value {
+	case "red", "green", "blue":
+		*c = testColor(value)
+		return nil
+	}
+	return fmt.Errorf("unknown color %q", value)
+}
+
+func TestParse_EnvUnmarshaler(t *testing.T) {
+	t.Setenv("COLOR", "red")
+	t.Setenv("COLORS", "red, green,blue")
+	type Env struct {
+		Color  testColor   `env:"COLOR"`
+		Colors []testColor `env:"COLORS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Color, testColor("red"))
+	assert.Equal(t, env.Colors, []testColor{"red", "green", "blue"})
+}
+
+func TestParse_EnvUnmarshaler_Slice_Error(t *testing.T) {
+	t.Setenv("COLORS", "red,purple")
+	type Env struct {
+		Colors []testColor `env:"COLORS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `element 1 ("purple")`)
+}
+
+type testVersion struct {
+	Major, Minor int
+}
+
+func (v *testVersion) Scan(src interface{}) error {
+	s, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("unsupported type %T", src)
+	}
+	if _, err := fmt.Sscanf(s, "%d.%d", &v.Major, &v.Minor); err != nil {
+		return fmt.Errorf("invalid version %q", s)
+	}
+	return nil
+}
+
+func TestParse_Scanner(t *testing.T) {
+	t.Setenv("VERSION", "1.2")
+	t.Setenv("NAME", "app")
+	type Env struct {
+		Version testVersion    `env:"VERSION"`
+		Name    sql.NullString `env:"NAME"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Version, testVersion{Major: 1, Minor: 2})
+	assert.Equal(t, env.Name, sql.NullString{String: "app", Valid: true})
+}
+
+func TestParse_Scanner_Error(t *testing.T) {
+	t.Setenv("VERSION", "one")
+	type Env struct {
+		Version testVersion `env:"VERSION"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid version "one"`)
+}
+
+func TestParse_ValuePrefix(t *testing.T) {
+	t.Setenv("SECRET", "vault:abc123")
+	t.Setenv("TOKEN", "plain")
+	type Env struct {
+		Secret string `env:"SECRET" valuePrefix:"vault:"`
+		Token  string `env:"TOKEN" valuePrefix:"vault:"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Secret, "abc123")
+	assert.Equal(t, env.Token, "plain")
+}
+
+func TestParse_ValuePrefix_Strict_Error(t *testing.T) {
+	t.Setenv("SECRET", "abc123")
+	type Env struct {
+		Secret string `env:"SECRET" valuePrefix:"vault:"`
+	}
+	var env Env
+	err := Parse(&env, WithStrictValuePrefix())
+	assert.Error(t, err)
+}
+
+func TestParse_DateAndTimeFormats(t *testing.T) {
+	t.Setenv("DATE", "2023-10-01")
+	t.Setenv("TIME", "15:04")
+	t.Setenv("TIME_SECONDS", "08:30:15")
+	type Env struct {
+		Date        time.Time `env:"DATE" format:"date"`
+		Time        time.Time `env:"TIME" format:"time"`
+		TimeSeconds time.Time `env:"TIME_SECONDS" format:"time"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Date, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, env.Time, time.Date(0, 1, 1, 15, 4, 0, 0, time.UTC))
+	assert.Equal(t, env.TimeSeconds, time.Date(0, 1, 1, 8, 30, 15, 0, time.UTC))
+}
+
+func TestParse_DateAndTimeFormats_Error(t *testing.T) {
+	t.Setenv("DATE", "2023-10-01T10:00:00Z")
+	t.Setenv("TIME", "25:00")
+	type DateEnv struct {
+		Date time.Time `env:"DATE" format:"date"`
+	}
+	var dateEnv DateEnv
+	err := Parse(&dateEnv)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid date "2023-10-01T10:00:00Z"`)
+
+	type TimeEnv struct {
+		Time time.Time `env:"TIME" format:"time"`
+	}
+	var timeEnv TimeEnv
+	err = Parse(&timeEnv)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid time "25:00"`)
+}
+
+func TestParse_BigRat(t *testing.T) {
+	t.Setenv("ASPECT", "16/9")
+	t.Setenv("SCALE", "3")
+	t.Setenv("RATIOS", "1/2, 0.75,2")
+	type Env struct {
+		Aspect *big.Rat   `env:"ASPECT"`
+		Scale  *big.Rat   `env:"SCALE"`
+		Ratios []*big.Rat `env:"RATIOS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Aspect.String(), "16/9")
+	assert.Equal(t, env.Scale.String(), "3/1")
+	assert.Len(t, env.Ratios, 3)
+	assert.Equal(t, env.Ratios[0].String(), "1/2")
+	assert.Equal(t, env.Ratios[1].String(), "3/4")
+	assert.Equal(t, env.Ratios[2].String(), "2/1")
+}
+
+func TestParse_BigRat_Error(t *testing.T) {
+	t.Setenv("ASPECT", "16:9")
+	t.Setenv("RATIOS", "1/2,1/0")
+	type Env struct {
+		Aspect *big.Rat `env:"ASPECT"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid rational number "16:9"`)
+
+	type SliceEnv struct {
+		Ratios []*big.Rat `env:"RATIOS"`
+	}
+	var sliceEnv SliceEnv
+	err = Parse(&sliceEnv)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `element 1 ("1/0")`)
+}
+
+func TestParse_ClockDuration(t *testing.T) {
+	t.Setenv("OFFSET", "01:30:00")
+	t.Setenv("TIMEOUT", "30:00")
+	t.Setenv("DAY", "24:00:05")
+	type Env struct {
+		Offset  time.Duration `env:"OFFSET" format:"clock"`
+		Timeout time.Duration `env:"TIMEOUT" format:"clock"`
+		Day     time.Duration `env:"DAY" format:"clock"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Offset, 90*time.Minute)
+	assert.Equal(t, env.Timeout, 30*time.Minute)
+	assert.Equal(t, env.Day, 24*time.Hour+5*time.Second)
+}
+
+func TestParse_ClockDuration_Error(t *testing.T) {
+	for _, val := range []string{"1:2:3:4", "90", "01:60", "aa:00"} {
+		t.Setenv("OFFSET", val)
+		type Env struct {
+			Offset time.Duration `env:"OFFSET" format:"clock"`
+		}
+		var env Env
+		err := Parse(&env)
+		assert.Error(t, err, val)
+		assert.Contains(t, err.Error(), "invalid clock duration")
+	}
+}
+
+func TestParse_Slices_EmptyTrimSkip(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	type Env struct {
+		Strings   []string        `env:"STRINGS" skipEmpty:"true"`
+		Ints      []int           `env:"INTS" skipEmpty:"true"`
+		Floats    []float64       `env:"FLOATS" skipEmpty:"true"`
+		Durations []time.Duration `env:"DURATIONS" skipEmpty:"true"`
+		Times     []time.Time     `env:"TIMES" skipEmpty:"true"`
+	}
+	tests := []struct {
+		name     string
+		vals     [5]string
+		expected Env
+	}{
+		{
+			name: "empty",
+			vals: [5]string{"", "", "", "", ""},
+			expected: Env{
+				Strings:   []string{},
+				Ints:      []int{},
+				Floats:    []float64{},
+				Durations: []time.Duration{},
+				Times:     []time.Time{},
+			},
+		},
+		{
+			name: "padded",
+			vals: [5]string{" a , b", "1 , 2 ", " 1.5,2", "1s , 2m", " 2024-01-01 , 2024-01-02"},
+			expected: Env{
+				Strings:   []string{"a", "b"},
+				Ints:      []int{1, 2},
+				Floats:    []float64{1.5, 2},
+				Durations: []time.Duration{time.Second, 2 * time.Minute},
+				Times:     []time.Time{day(1), day(2)},
+			},
+		},
+		{
+			name: "doubled separator",
+			vals: [5]string{"a,,b,", "1,,2,", "1.5,,2,", "1s,,2m,", "2024-01-01,,2024-01-02,"},
+			expected: Env{
+				Strings:   []string{"a", "b"},
+				Ints:      []int{1, 2},
+				Floats:    []float64{1.5, 2},
+				Durations: []time.Duration{time.Second, 2 * time.Minute},
+				Times:     []time.Time{day(1), day(2)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, key := range []string{"STRINGS", "INTS", "FLOATS", "DURATIONS", "TIMES"} {
+				t.Setenv(key, tt.vals[i])
+			}
+			var env Env
+			err := Parse(&env)
+			assert.NoError(t, err)
+			assert.Equal(t, env, tt.expected)
+		})
+	}
+}
+
+func TestParse_When(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "production")
+	t.Setenv("TLS_CERT", "/etc/cert.pem")
+	t.Setenv("DEBUG_PORT", "6060")
+	type Env struct {
+		TLSCert   string `env:"TLS_CERT" when:"ENVIRONMENT=production"`
+		DebugPort int    `env:"DEBUG_PORT" when:"ENVIRONMENT=development"`
+		LogLevel  string `env:"LOG_LEVEL" when:"ENVIRONMENT!=production"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.TLSCert, "/etc/cert.pem")
+	assert.Equal(t, env.DebugPort, 0)
+	assert.Equal(t, env.LogLevel, "")
+}
+
+func TestParse_When_Error(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "production")
+	type Env struct {
+		TLSCert string `env:"TLS_CERT" when:"ENVIRONMENT=production"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required environment variables: TLS_CERT")
+
+	type Invalid struct {
+		TLSCert string `env:"TLS_CERT" when:"ENVIRONMENT"`
+	}
+	var invalid Invalid
+	err = Parse(&invalid)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid when condition")
+}
+
+func TestParse_Slice_ElementError(t *testing.T) {
+	t.Setenv("INTS", "1,2,3e")
+	t.Setenv("FLOATS", "1.5,x")
+	t.Setenv("DURATIONS", "1s,5x")
+	t.Setenv("UINTS", "1,-2")
+	type Env struct {
+		Ints      []int           `env:"INTS"`
+		Floats    []float64       `env:"FLOATS"`
+		Durations []time.Duration `env:"DURATIONS"`
+		Uints     []uint32        `env:"UINTS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `env 'INTS': element 2 ("3e"): invalid syntax`)
+	assert.Contains(t, err.Error(), `env 'FLOATS': element 1 ("x"): invalid syntax`)
+	assert.Contains(t, err.Error(), `env 'DURATIONS': element 1 ("5x"): time: unknown unit`)
+	assert.Contains(t, err.Error(), `env 'UINTS': element 1 ("-2"): invalid syntax`)
+}
+
+func TestParse_Suffix(t *testing.T) {
+	t.Setenv("LATENCY", "50ms")
+	t.Setenv("RATIO", "12.5 %")
+	t.Setenv("TIMEOUTS", "10ms, 20ms")
+	type Env struct {
+		Latency  int     `env:"LATENCY" suffix:"ms"`
+		Ratio    float64 `env:"RATIO" suffix:"%"`
+		Timeouts []int   `env:"TIMEOUTS" suffix:"ms"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Latency, 50)
+	assert.Equal(t, env.Ratio, 12.5)
+	assert.Equal(t, env.Timeouts, []int{10, 20})
+}
+
+func TestParse_Suffix_Error(t *testing.T) {
+	t.Setenv("LATENCY", "50")
+	type Env struct {
+		Latency int `env:"LATENCY" suffix:"ms"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `value "50" does not end with "ms"`)
+}
+
+func TestParse_Slice_ElemDefault(t *testing.T) {
+	t.Setenv("RATES", "1, ,3,")
+	type Env struct {
+		Rates   []int    `env:"RATES" elemDefault:"5"`
+		Weights []int    `env:"WEIGHTS" default:"2,,4" elemDefault:"3"`
+		Names   []string `env:"NAMES" default:"a,b"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Rates, []int{1, 5, 3, 5})
+	assert.Equal(t, env.Weights, []int{2, 3, 4})
+	assert.Equal(t, env.Names, []string{"a", "b"})
+}
+
+func TestParse_Slice_ElemDefault_Error(t *testing.T) {
+	t.Setenv("RATES", "1,,3")
+	type Env struct {
+		Rates []int `env:"RATES" elemDefault:"five"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `element 1 ("five")`)
+}
+
+type testPoint struct {
+	X, Y int
+}
+
+func (pt *testPoint) UnmarshalText(text []byte) error {
+	if _, err := fmt.Sscanf(string(text), "%d:%d", &pt.X, &pt.Y); err != nil {
+		return fmt.Errorf("invalid point %q", text)
+	}
+	return nil
+}
+
+func TestParse_CompositeConverters(t *testing.T) {
+	t.Setenv("POINT", "1:2")
+	t.Setenv("POINTS", "1:2, 3:4")
+	t.Setenv("NAMED_POINTS", "a=5:6,b=7:8")
+	t.Setenv("LEVELS", "debug,error")
+	t.Setenv("MODULE_LEVELS", "db=info,http=error")
+	t.Setenv("IPS", "10.0.0.1,::1")
+	type Env struct {
+		Point        testPoint            `env:"POINT"`
+		Points       []testPoint          `env:"POINTS"`
+		NamedPoints  map[string]testPoint `env:"NAMED_POINTS"`
+		Levels       []testLevel          `env:"LEVELS"`
+		ModuleLevels map[string]testLevel `env:"MODULE_LEVELS"`
+		IPs          []net.IP             `env:"IPS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Point, testPoint{1, 2})
+	assert.Equal(t, env.Points, []testPoint{{1, 2}, {3, 4}})
+	assert.Equal(t, env.NamedPoints, map[string]testPoint{"a": {5, 6}, "b": {7, 8}})
+	assert.Equal(t, env.Levels, []testLevel{levelDebug, levelError})
+	assert.Equal(t, env.ModuleLevels, map[string]testLevel{"db": levelInfo, "http": levelError})
+	assert.Len(t, env.IPs, 2)
+	assert.True(t, env.IPs[0].Equal(net.ParseIP("10.0.0.1")))
+	assert.True(t, env.IPs[1].Equal(net.IPv6loopback))
+}
+
+func TestParse_CompositeConverters_Error(t *testing.T) {
+	t.Setenv("POINTS", "1:2,3")
+	t.Setenv("MODULE_LEVELS", "db=verbose")
+	type Env struct {
+		Points       []testPoint          `env:"POINTS"`
+		ModuleLevels map[string]testLevel `env:"MODULE_LEVELS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `element 1 ("3"): invalid point "3"`)
+	assert.Contains(t, err.Error(), `unknown envparser.testLevel value "verbose"`)
+}
+
+func TestParse_BoolInt(t *testing.T) {
+	t.Setenv("ENABLED", "true")
+	t.Setenv("DISABLED", "FALSE")
+	t.Setenv("COUNT", "1")
+	type Env struct {
+		Enabled  int  `env:"ENABLED" boolint:"true"`
+		Disabled uint `env:"DISABLED" boolint:"true"`
+		Count    int  `env:"COUNT" boolint:"true"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Enabled, 1)
+	assert.Equal(t, env.Disabled, uint(0))
+	assert.Equal(t, env.Count, 1)
+}
+
+func TestParse_BoolInt_Error(t *testing.T) {
+	t.Setenv("ENABLED", "yes")
+	type Env struct {
+		Enabled int `env:"ENABLED" boolint:"true"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+
+	t.Setenv("ENABLED", "true")
+	type Plain struct {
+		Enabled int `env:"ENABLED"`
+	}
+	var plain Plain
+	err = Parse(&plain)
+	assert.Error(t, err)
+}
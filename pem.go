@@ -0,0 +1,58 @@
+package envparser
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	rsaPrivateKeyType   = reflect.TypeOf((*rsa.PrivateKey)(nil))
+	ecdsaPrivateKeyType = reflect.TypeOf((*ecdsa.PrivateKey)(nil))
+)
+
+// setPrivateKey parses a PEM encoded PKCS1, PKCS8 or SEC 1 (EC) private key
+// into a *rsa.PrivateKey or *ecdsa.PrivateKey field. Escaped "\n" sequences
+// are accepted in place of newlines, since multi-line values are awkward in
+// many environments.
+func setPrivateKey(field reflect.Value, val string) error {
+	if field.Type() != rsaPrivateKeyType && field.Type() != ecdsaPrivateKeyType {
+		return fmt.Errorf("pem format requires a *rsa.PrivateKey or *ecdsa.PrivateKey field, got %s", field.Type())
+	}
+	if !strings.Contains(val, "\n") {
+		val = strings.Replace(val, `\n`, "\n", -1)
+	}
+
+	block, _ := pem.Decode([]byte(val))
+	if block == nil {
+		return errors.New("no PEM data found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	kv := reflect.ValueOf(key)
+	if kv.Type() != field.Type() {
+		return fmt.Errorf("PEM contains a %s, not a %s", kv.Type(), field.Type())
+	}
+	field.Set(kv)
+	return nil
+}
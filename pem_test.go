@@ -0,0 +1,56 @@
+package envparser
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_PrivateKey_PKCS8(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+	encoded := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	t.Setenv("SIGNING_KEY", strings.Replace(encoded, "\n", `\n`, -1))
+
+	type Env struct {
+		SigningKey *ecdsa.PrivateKey `env:"SIGNING_KEY" format:"pem"`
+	}
+	var env Env
+	err = Parse(&env)
+	assert.NoError(t, err)
+	assert.True(t, key.Equal(env.SigningKey))
+}
+
+func TestParse_PrivateKey_WrongType_Error(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	t.Setenv("SIGNING_KEY", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})))
+
+	type Env struct {
+		SigningKey *rsa.PrivateKey `env:"SIGNING_KEY" format:"pem"`
+	}
+	var env Env
+	err = Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_PrivateKey_Error(t *testing.T) {
+	t.Setenv("SIGNING_KEY", "not a key")
+	type Env struct {
+		SigningKey *rsa.PrivateKey `env:"SIGNING_KEY" format:"pem"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
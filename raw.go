@@ -0,0 +1,60 @@
+package envparser
+
+import "strings"
+
+// recordRaw remembers the raw value found for key. It is safe for concurrent
+// use.
+func (p *Parser) recordRaw(key, val string, ok bool) {
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.raw == nil {
+		p.raw = map[string]string{}
+	}
+	p.raw[key] = val
+}
+
+// markSecret remembers that key backs a field tagged secret:"true". It is
+// safe for concurrent use.
+func (p *Parser) markSecret(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.secrets == nil {
+		p.secrets = map[string]bool{}
+	}
+	p.secrets[key] = true
+}
+
+// isSecret reports whether key backs a secret field, including the numbered
+// variables of an indexed secret slice.
+func (p *Parser) isSecret(key string) bool {
+	if p.secrets[key] {
+		return true
+	}
+	for secret := range p.secrets {
+		if strings.HasPrefix(key, secret+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseWithRaw parses target like Parse and returns the raw value of every
+// variable that was read, keyed by the name that was looked up, for attaching
+// to bug reports. Values of secret:"true" fields are masked and others pass
+// through the WithRedactor function when one is set. The values are returned
+// even when parsing fails.
+func (p *Parser) ParseWithRaw(target interface{}) (map[string]string, error) {
+	err := p.Parse(target)
+	raw := make(map[string]string, len(p.raw))
+	for key, val := range p.raw {
+		if p.isSecret(key) {
+			raw[key] = redacted
+			continue
+		}
+		raw[key] = p.redactValue(key, val)
+	}
+	return raw, err
+}
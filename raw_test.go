@@ -0,0 +1,31 @@
+package envparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseWithRaw(t *testing.T) {
+	t.Setenv("HOST", " localhost ")
+	t.Setenv("PASSWORD", "hunter2")
+	t.Setenv("HOSTS", "a, b")
+	t.Setenv("KEY_0", "k0")
+	t.Setenv("UNRELATED", "x")
+	type Env struct {
+		Host     string   `env:"HOST"`
+		Port     int      `env:"PORT" default:"8080"`
+		Password string   `env:"PASSWORD" secret:"true"`
+		Hosts    []string `env:"HOSTS"`
+		Keys     []string `env:"KEY" indexed:"true" secret:"true"`
+	}
+	var env Env
+	raw, err := New().ParseWithRaw(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, map[string]string{
+		"HOST":     " localhost ",
+		"PASSWORD": "******",
+		"HOSTS":    "a, b",
+		"KEY_0":    "******",
+	})
+}
@@ -0,0 +1,86 @@
+package envparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// redactedError is an error whose message has had a variable's value masked
+// by the WithRedactor function. It unwraps to the outermost error in the
+// original chain whose message does not contain the value, such as
+// strconv.ErrSyntax for a *strconv.NumError, so errors.Is and errors.As
+// cannot reach the unmasked value.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redact masks val, the raw value of key, in the message of err using the
+// configured redactor. Elements of list values are masked where they appear
+// quoted, as in element errors.
+func (p *Parser) redact(key, val string, err error) error {
+	if err == nil || p.redactor == nil || val == "" {
+		return err
+	}
+	masked := p.redactor(key, val)
+	if masked == val {
+		return err
+	}
+
+	msg := strings.Replace(err.Error(), val, masked, -1)
+	for _, part := range strings.Split(val, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			msg = strings.Replace(msg, strconv.Quote(part), strconv.Quote(p.redactor(key, part)), -1)
+		}
+	}
+	return &redactedError{msg: msg, err: safeCause(err, val)}
+}
+
+// safeCause returns the first error in the Unwrap chain of err whose message
+// reveals neither val nor any of its list elements, or nil if there is none.
+func safeCause(err error, val string) error {
+	for ; err != nil; err = unwrapOnce(err) {
+		if !revealsValue(err.Error(), val) {
+			return err
+		}
+	}
+	return nil
+}
+
+func unwrapOnce(err error) error {
+	if u, ok := err.(interface{ Unwrap() error }); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// revealsValue reports whether msg contains val or one of its
+// comma-separated elements.
+func revealsValue(msg, val string) bool {
+	if strings.Contains(msg, val) {
+		return true
+	}
+	for _, part := range strings.Split(val, ",") {
+		if part = strings.TrimSpace(part); part != "" && strings.Contains(msg, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue returns the form of val, the value of key, that may appear in
+// reports.
+func (p *Parser) redactValue(key, val string) string {
+	if p.redactor == nil {
+		return val
+	}
+	return p.redactor(key, val)
+}
@@ -0,0 +1,245 @@
+package envparser
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	enumMaps   = map[string]map[string]int{}
+	flagMaps   = map[string]map[string]uint{}
+	variants   = map[string]map[string]reflect.Type{}
+	enumTypes  = map[reflect.Type]map[string]reflect.Value{}
+	transforms = map[string]func(string) (string, error){}
+)
+
+// RegisterEnumMap registers a mapping from names to integer values that
+// fields can reference with the enum tag, e.g. enum:"STATE" after
+// RegisterEnumMap("STATE", map[string]int{"idle": 0, "running": 1}).
+func RegisterEnumMap(name string, values map[string]int) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	enumMaps[name] = values
+}
+
+// setEnum resolves val through the enum map registered as name and stores the
+// result in the integer field.
+func setEnum(field reflect.Value, name, val string) error {
+	registryMu.RLock()
+	values, ok := enumMaps[name]
+	registryMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("enum %q is not registered", name)
+	}
+
+	n, ok := values[val]
+	if !ok {
+		return fmt.Errorf("unknown %s value %q, expected one of %s", name, val, strings.Join(sortedKeys(values), ", "))
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.OverflowInt(int64(n)) {
+			return fmt.Errorf("enum value %d overflows %s", n, field.Type())
+		}
+		field.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n < 0 || field.OverflowUint(uint64(n)) {
+			return fmt.Errorf("enum value %d overflows %s", n, field.Type())
+		}
+		field.SetUint(uint64(n))
+	default:
+		return fmt.Errorf("enum requires an integer field, got %s", field.Type())
+	}
+	return nil
+}
+
+// RegisterEnum registers the valid values of an enum type whose values
+// implement fmt.Stringer, given as a slice such as []Color{Red, Green, Blue}.
+// Fields of that type are then set to the value whose String() matches the
+// variable, without needing a tag.
+func RegisterEnum(values interface{}) {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice || !v.Type().Elem().Implements(stringerType) {
+		panic(fmt.Sprintf("envparser: RegisterEnum requires a slice of fmt.Stringer values, got %T", values))
+	}
+	byName := make(map[string]reflect.Value, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		byName[v.Index(i).Interface().(fmt.Stringer).String()] = v.Index(i)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	enumTypes[v.Type().Elem()] = byName
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// setStringerEnum sets field to the registered value of its type whose
+// String() equals val. It reports false when the type is not registered.
+func setStringerEnum(field reflect.Value, val string) (bool, error) {
+	registryMu.RLock()
+	byName, ok := enumTypes[field.Type()]
+	registryMu.RUnlock()
+	if !ok {
+		return false, nil
+	}
+	v, ok := byName[val]
+	if !ok {
+		names := make([]string, 0, len(byName))
+		for k := range byName {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		return true, fmt.Errorf("unknown %s value %q, expected one of %s", field.Type(), val, strings.Join(names, ", "))
+	}
+	field.Set(v)
+	return true, nil
+}
+
+// RegisterFlags registers a mapping from names to bit values that fields can
+// reference with the flags tag. A comma-separated value such as "read,write"
+// sets the field to the OR of the named bits.
+func RegisterFlags(name string, values map[string]uint) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	flagMaps[name] = values
+}
+
+// setFlags ORs together the bits named in the comma-separated val using the
+// flag map registered as name.
+func (p *Parser) setFlags(field reflect.Value, fieldType reflect.StructField, name, val string) error {
+	registryMu.RLock()
+	values, ok := flagMaps[name]
+	registryMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("flags %q is not registered", name)
+	}
+
+	var bits uint64
+	for _, flag := range p.splitList(val, fieldType.Tag) {
+		if flag == "" {
+			continue
+		}
+		bit, ok := values[flag]
+		if !ok {
+			names := make([]string, 0, len(values))
+			for k := range values {
+				names = append(names, k)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown %s flag %q, expected any of %s", name, flag, strings.Join(names, ", "))
+		}
+		bits |= uint64(bit)
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if bits > 1<<63-1 || field.OverflowInt(int64(bits)) {
+			return fmt.Errorf("flags %#x overflow %s", bits, field.Type())
+		}
+		field.SetInt(int64(bits))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if field.OverflowUint(bits) {
+			return fmt.Errorf("flags %#x overflow %s", bits, field.Type())
+		}
+		field.SetUint(bits)
+	default:
+		return fmt.Errorf("flags require an integer field, got %s", field.Type())
+	}
+	return nil
+}
+
+// RegisterVariants registers the element types of a heterogeneous list that
+// []interface{} fields can reference with the variants tag. Each element of
+// the value is written as "name:value" and decoded into a new value of the
+// type of the prototype registered under name, e.g. "resize:100,watermark:logo"
+// after RegisterVariants("STEPS", map[string]interface{}{"resize": 0, "watermark": ""}).
+func RegisterVariants(name string, prototypes map[string]interface{}) {
+	types := make(map[string]reflect.Type, len(prototypes))
+	for k, v := range prototypes {
+		types[k] = reflect.TypeOf(v)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	variants[name] = types
+}
+
+// setVariants decodes the comma-separated "name:value" elements of val into
+// the types registered as name and stores them in the []interface{} field.
+func (p *Parser) setVariants(field reflect.Value, fieldType reflect.StructField, name, val string) error {
+	registryMu.RLock()
+	types, ok := variants[name]
+	registryMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("variants %q is not registered", name)
+	}
+	if field.Kind() != reflect.Slice || field.Type().Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("variants require a []interface{} field, got %s", field.Type())
+	}
+
+	parts := p.splitList(val, fieldType.Tag)
+	list := reflect.MakeSlice(field.Type(), 0, len(parts))
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, ":", 2)
+		t, ok := types[kv[0]]
+		if !ok {
+			names := make([]string, 0, len(types))
+			for k := range types {
+				names = append(names, k)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("element %d: unknown %s variant %q, expected one of %s", i, name, kv[0], strings.Join(names, ", "))
+		}
+		if len(kv) != 2 {
+			return fmt.Errorf("element %d (%q) is missing ':'", i, part)
+		}
+		elem := reflect.New(t).Elem()
+		if err := p.setValueFromEnv(elem, reflect.StructField{Type: t}, kv[1]); err != nil {
+			return &elementError{index: i, value: part, err: err}
+		}
+		list = reflect.Append(list, elem)
+	}
+	field.Set(list)
+	return nil
+}
+
+// RegisterTransform registers a function that rewrites the raw value of a
+// fallback key before conversion. An env tag such as
+// env:"TIMEOUT;TIMEOUT_MS|ms" applies the transform registered as "ms" only
+// when TIMEOUT_MS supplies the value.
+func RegisterTransform(name string, fn func(string) (string, error)) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	transforms[name] = fn
+}
+
+// applyTransform rewrites val with the transform registered as name.
+func applyTransform(name, val string) (string, error) {
+	registryMu.RLock()
+	fn, ok := transforms[name]
+	registryMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("transform %q is not registered", name)
+	}
+	out, err := fn(val)
+	if err != nil {
+		return "", fmt.Errorf("transform %s: %v", name, err)
+	}
+	return out, nil
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
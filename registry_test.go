@@ -0,0 +1,185 @@
+package envparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testState int
+
+const (
+	stateIdle testState = iota
+	stateRunning
+	stateStopped
+)
+
+func init() {
+	RegisterEnumMap("STATE_ENUM", map[string]int{
+		"idle":    int(stateIdle),
+		"running": int(stateRunning),
+		"stopped": int(stateStopped),
+	})
+}
+
+type testPerm uint8
+
+const (
+	permRead testPerm = 1 << iota
+	permWrite
+	permExec
+)
+
+func init() {
+	RegisterFlags("PERMS", map[string]uint{
+		"read":  uint(permRead),
+		"write": uint(permWrite),
+		"exec":  uint(permExec),
+	})
+}
+
+func TestParse_EnumMap(t *testing.T) {
+	t.Setenv("STATE", "running")
+	type Env struct {
+		State testState `env:"STATE" enum:"STATE_ENUM"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.State, stateRunning)
+}
+
+func TestParse_EnumMap_Error(t *testing.T) {
+	t.Setenv("STATE", "paused")
+	type Env struct {
+		State testState `env:"STATE" enum:"STATE_ENUM"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "idle, running, stopped")
+}
+
+func TestParse_EnumMapSlice(t *testing.T) {
+	t.Setenv("STATES", "idle, stopped,running")
+	type Env struct {
+		States []testState `env:"STATES" enum:"STATE_ENUM"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.States, []testState{stateIdle, stateStopped, stateRunning})
+}
+
+func TestParse_EnumMapSlice_Error(t *testing.T) {
+	t.Setenv("STATES", "idle,paused")
+	type Env struct {
+		States []testState `env:"STATES" enum:"STATE_ENUM"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `element 1 ("paused")`)
+	assert.Contains(t, err.Error(), "idle, running, stopped")
+}
+
+func TestParse_Flags(t *testing.T) {
+	t.Setenv("FLAGS", "read, write")
+	type Env struct {
+		Flags testPerm `env:"FLAGS" flags:"PERMS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Flags, permRead|permWrite)
+}
+
+func TestParse_Flags_Error(t *testing.T) {
+	t.Setenv("FLAGS", "read,delete")
+	type Env struct {
+		Flags testPerm `env:"FLAGS" flags:"PERMS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func init() {
+	RegisterVariants("STEPS", map[string]interface{}{
+		"resize":    0,
+		"watermark": "",
+		"tint":      testColor(""),
+	})
+}
+
+func TestParse_Variants(t *testing.T) {
+	t.Setenv("STEPS", "resize:100, watermark:logo,tint:red")
+	type Env struct {
+		Steps []interface{} `env:"STEPS" variants:"STEPS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Steps, []interface{}{100, "logo", testColor("red")})
+}
+
+func TestParse_Variants_Error(t *testing.T) {
+	t.Setenv("STEPS", "resize:100,crop:10")
+	type Env struct {
+		Steps []interface{} `env:"STEPS" variants:"STEPS"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown STEPS variant "crop"`)
+}
+
+type testLevel int
+
+const (
+	levelDebug testLevel = iota
+	levelInfo
+	levelError
+)
+
+func (l testLevel) String() string {
+	switch l {
+	case levelDebug:
+		return "debug"
+	case levelInfo:
+		return "info"
+	case levelError:
+		return "error"
+	}
+	return "unknown"
+}
+
+func init() {
+	RegisterEnum([]testLevel{levelDebug, levelInfo, levelError})
+}
+
+func TestParse_StringerEnum(t *testing.T) {
+	t.Setenv("LEVEL", "error")
+	type Env struct {
+		Level testLevel `env:"LEVEL"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Level, levelError)
+}
+
+func TestParse_StringerEnum_Error(t *testing.T) {
+	t.Setenv("LEVEL", "warn")
+	type Env struct {
+		Level testLevel `env:"LEVEL"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown envparser.testLevel value "warn", expected one of debug, error, info`)
+}
+
+func TestRegisterEnum_Panics(t *testing.T) {
+	assert.Panics(t, func() { RegisterEnum([]int{1, 2}) })
+}
@@ -0,0 +1,39 @@
+package envparser
+
+import "sort"
+
+// Report lists the variables behind the fields of a parse by where their
+// values came from.
+type Report struct {
+	// Defaulted holds the variables that were unset, so the field used its
+	// default.
+	Defaulted []string
+	// FromEnv holds the variables whose value was read.
+	FromEnv []string
+	// Missing holds the variables that were unset and had no default.
+	Missing []string
+	// UnusedDefaults holds the variables that have a default which went
+	// unused because the variable was set.
+	UnusedDefaults []string
+}
+
+// unusedDefault records that the default of key was shadowed by its value.
+// It is safe for concurrent use.
+func (p *Parser) unusedDefault(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.report.UnusedDefaults = append(p.report.UnusedDefaults, key)
+}
+
+// ParseWithReport parses target like Parse and reports, in sorted order,
+// which variables were read, which fell back to defaults, which were missing
+// and which had a default that the read value made unused. The report is
+// returned even when parsing fails.
+func (p *Parser) ParseWithReport(target interface{}) (Report, error) {
+	err := p.Parse(target)
+	report := p.report
+	for _, keys := range [][]string{report.Defaulted, report.FromEnv, report.Missing, report.UnusedDefaults} {
+		sort.Strings(keys)
+	}
+	return report, err
+}
@@ -0,0 +1,51 @@
+package envparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseWithReport(t *testing.T) {
+	t.Setenv("HOST", "localhost")
+	t.Setenv("TOKEN", "abc")
+	type Env struct {
+		Host     string `env:"HOST"`
+		Port     int    `env:"PORT" default:"8080"`
+		Token    string `env:"TOKEN" group:"auth"`
+		Password string `env:"PASSWORD" group:"auth"`
+	}
+	var env Env
+	report, err := New().ParseWithReport(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, report.FromEnv, []string{"HOST", "TOKEN"})
+	assert.Equal(t, report.Defaulted, []string{"PORT"})
+	assert.Equal(t, report.Missing, []string{"PASSWORD"})
+	assert.Empty(t, report.UnusedDefaults)
+}
+
+func TestParser_ParseWithReport_UnusedDefaults(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	t.Setenv("HOST", "localhost")
+	type Env struct {
+		Host  string `env:"HOST"`
+		Port  int    `env:"PORT" default:"8080"`
+		Debug bool   `env:"DEBUG" default:"false"`
+	}
+	var env Env
+	report, err := New().ParseWithReport(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, report.UnusedDefaults, []string{"PORT"})
+	assert.Equal(t, report.Defaulted, []string{"DEBUG"})
+}
+
+func TestParser_ParseWithReport_Error(t *testing.T) {
+	type Env struct {
+		Name string `env:"NAME"`
+	}
+	var env Env
+	report, err := New().ParseWithReport(&env)
+	assert.Error(t, err)
+	assert.Equal(t, report.Missing, []string{"NAME"})
+	assert.Empty(t, report.FromEnv)
+}
@@ -0,0 +1,142 @@
+package envparser
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// redacted replaces the values of secret fields wherever they are reported.
+const redacted = "******"
+
+// parsedField is a field that was populated during a parse. sources are the
+// variables that were actually read, which differ from key when a normalized
+// spelling or fallback key matched, or for the numbered variables of an
+// indexed slice.
+type parsedField struct {
+	key       string
+	sources   []string
+	value     reflect.Value
+	fieldType reflect.StructField
+}
+
+// recordField remembers that the field backed by key was populated from the
+// variables sources. It is safe for concurrent use.
+func (p *Parser) recordField(key string, sources []string, value reflect.Value, fieldType reflect.StructField) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fields = append(p.fields, parsedField{key: key, sources: sources, value: value, fieldType: fieldType})
+}
+
+// markEnvRead remembers that key was read from the process environment. It is
+// safe for concurrent use.
+func (p *Parser) markEnvRead(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.envRead == nil {
+		p.envRead = map[string]bool{}
+	}
+	p.envRead[key] = true
+}
+
+// unsetSecretKeys removes the variables that supplied secret:"true" fields
+// and catch-all maps from the WithMapEnviron map when one is set, and
+// otherwise from the process environment. Variables served by other sources
+// or dotenv files are left alone.
+func (p *Parser) unsetSecretKeys() {
+	var keys []string
+	for _, f := range p.fields {
+		if f.fieldType.Tag.Get("secret") == "true" {
+			keys = append(keys, f.sources...)
+		}
+	}
+	for _, c := range p.catchAll {
+		if c.secret {
+			keys = append(keys, c.keys...)
+		}
+	}
+	for _, key := range keys {
+		if p.environ != nil {
+			delete(p.environ, key)
+		} else if p.envRead[key] {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+// ParseWithSnapshot parses target like Parse and returns the effective value
+// of every populated field keyed by variable name, for logging the running
+// configuration. Values of fields tagged secret:"true" are masked, and other
+// values pass through the WithRedactor function when one is set.
+func (p *Parser) ParseWithSnapshot(target interface{}) (map[string]string, error) {
+	if err := p.Parse(target); err != nil {
+		return nil, err
+	}
+
+	snapshot := make(map[string]string, len(p.fields))
+	for _, f := range p.fields {
+		if f.fieldType.Tag.Get("secret") == "true" {
+			snapshot[f.key] = redacted
+			continue
+		}
+		snapshot[f.key] = p.redactValue(f.key, formatValue(f.value, f.fieldType.Tag))
+	}
+	return snapshot, nil
+}
+
+// formatValue renders v in the same textual form Parse accepts, so that lists
+// become comma-separated and maps become sorted key=value pairs.
+func formatValue(v reflect.Value, tag reflect.StructTag) string {
+	switch tag.Get("encoding") {
+	case "json":
+		if b, err := json.Marshal(v.Interface()); err == nil {
+			return string(b)
+		}
+	case "base64":
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes())
+		}
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return value.String()
+	case encoding.TextMarshaler:
+		if b, err := value.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes())
+		}
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = formatValue(v.Index(i), "")
+		}
+		return strings.Join(parts, ",")
+	case reflect.Map:
+		parts := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			parts = append(parts, formatValue(k, "")+"="+formatValue(v.MapIndex(k), ""))
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, ",")
+	case reflect.Ptr:
+		if v.IsNil() {
+			return ""
+		}
+		return formatValue(v.Elem(), tag)
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,32 @@
+package envparser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseWithSnapshot(t *testing.T) {
+	t.Setenv("HOST", "localhost")
+	t.Setenv("PASSWORD", "hunter2")
+	t.Setenv("HOSTS", "a,b")
+	t.Setenv("TIMEOUT", "90s")
+	type Env struct {
+		Host     string        `env:"HOST"`
+		Port     int           `env:"PORT" default:"8080"`
+		Password string        `env:"PASSWORD" secret:"true"`
+		Hosts    []string      `env:"HOSTS"`
+		Timeout  time.Duration `env:"TIMEOUT"`
+	}
+	var env Env
+	snapshot, err := New().ParseWithSnapshot(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, map[string]string{
+		"HOST":     "localhost",
+		"PORT":     "8080",
+		"PASSWORD": "******",
+		"HOSTS":    "a,b",
+		"TIMEOUT":  "1m30s",
+	})
+}
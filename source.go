@@ -0,0 +1,95 @@
+package envparser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Source supplies the raw values of variables, such as the process
+// environment, files or a remote store. Lookup reports whether key is set and
+// returns an error only when the source itself fails.
+type Source interface {
+	Lookup(key string) (string, bool, error)
+}
+
+// KeyedSource is a Source that can also list the variables it holds.
+// Indexed slices and catch-all maps discover their variables through Keys, so
+// they require every configured source to implement it.
+type KeyedSource interface {
+	Source
+	Keys() []string
+}
+
+// SourceFunc adapts a function to the Source interface.
+type SourceFunc func(key string) (string, bool, error)
+
+// Lookup calls f(key).
+func (f SourceFunc) Lookup(key string) (string, bool, error) {
+	return f(key)
+}
+
+// MapSource serves values from a map, which is handy for tests and for
+// values assembled by the application.
+type MapSource map[string]string
+
+// Lookup returns the entry for key.
+func (m MapSource) Lookup(key string) (string, bool, error) {
+	val, ok := m[key]
+	return val, ok, nil
+}
+
+// Keys returns the keys of m.
+func (m MapSource) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// EnvSource returns a KeyedSource backed by os.LookupEnv and os.Environ. It is
+// used when no source is configured.
+func EnvSource() Source {
+	return envSource{}
+}
+
+type envSource struct{}
+
+func (envSource) Lookup(key string) (string, bool, error) {
+	val, ok := os.LookupEnv(key)
+	return val, ok, nil
+}
+
+func (envSource) Keys() []string {
+	var keys []string
+	for _, kv := range os.Environ() {
+		if i := strings.IndexByte(kv, '='); i > 0 {
+			keys = append(keys, kv[:i])
+		}
+	}
+	return keys
+}
+
+// activeSources returns the sources consulted for values: the WithMapEnviron
+// map, the configured sources, or the process environment.
+func (p *Parser) activeSources() []Source {
+	if p.environ != nil {
+		return []Source{MapSource(p.environ)}
+	}
+	if len(p.sources) == 0 {
+		return []Source{EnvSource()}
+	}
+	return p.sources
+}
+
+// checkKeyedSources fails unless every active source can list its keys, as
+// the discovery of indexed slices and catch-all maps requires.
+func (p *Parser) checkKeyedSources() error {
+	for _, s := range p.activeSources() {
+		if _, ok := s.(KeyedSource); !ok {
+			return fmt.Errorf("source %T cannot list its variables; indexed slices and catch-all maps require every source to implement KeyedSource", s)
+		}
+	}
+	return nil
+}
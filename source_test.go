@@ -0,0 +1,81 @@
+package envparser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_WithSources(t *testing.T) {
+	t.Setenv("HOST", "env-host")
+	t.Setenv("PORT", "9090")
+	type Env struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+		Name string `env:"NAME"`
+	}
+	var env Env
+	err := Parse(&env, WithSources(MapSource{"HOST": "map-host", "NAME": "app"}, EnvSource()))
+	assert.NoError(t, err)
+	assert.Equal(t, env.Host, "map-host")
+	assert.Equal(t, env.Port, 9090)
+	assert.Equal(t, env.Name, "app")
+}
+
+func TestParse_WithSource_ReplacesEnv(t *testing.T) {
+	t.Setenv("PORT", "9090")
+	type Env struct {
+		Port int `env:"PORT"`
+	}
+	var env Env
+	err := Parse(&env, WithSource(MapSource{}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required environment variables: PORT")
+}
+
+func TestParse_WithSource_Error(t *testing.T) {
+	failing := SourceFunc(func(key string) (string, bool, error) {
+		return "", false, errors.New("store unavailable")
+	})
+	type Env struct {
+		Port int `env:"PORT" default:"8080"`
+	}
+	var env Env
+	err := Parse(&env, WithSources(failing, EnvSource()))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "env 'PORT': store unavailable")
+}
+
+func TestParse_WithSource_KeyedDiscovery(t *testing.T) {
+	t.Setenv("ITEM_0", "from-env")
+	source := MapSource{"ITEM_0": "a", "ITEM_1": "b", "APP_MODE": "dev", "APP_REGION": "eu"}
+	type Env struct {
+		Items []string          `env:"ITEM" indexed:"true"`
+		Rest  map[string]string `env:"*" prefix:"APP_"`
+	}
+	var env Env
+	err := Parse(&env, WithSource(source))
+	assert.NoError(t, err)
+	assert.Equal(t, env.Items, []string{"a", "b"})
+	assert.Equal(t, env.Rest, map[string]string{"APP_MODE": "dev", "APP_REGION": "eu"})
+}
+
+func TestParse_WithSource_KeyedDiscovery_Error(t *testing.T) {
+	opaque := SourceFunc(func(key string) (string, bool, error) {
+		return "", false, nil
+	})
+	type Indexed struct {
+		Items []string `env:"ITEM" indexed:"true"`
+	}
+	err := Parse(&Indexed{}, WithSources(EnvSource(), opaque))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "env 'ITEM': source envparser.SourceFunc cannot list its variables")
+
+	type CatchAll struct {
+		Rest map[string]string `env:"*" prefix:"APP_"`
+	}
+	err = Parse(&CatchAll{}, WithSource(opaque))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "source envparser.SourceFunc cannot list its variables")
+}
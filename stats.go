@@ -0,0 +1,45 @@
+package envparser
+
+import "time"
+
+// Stats describes the work done by a parse.
+type Stats struct {
+	// Fields is the number of fields bound to a variable that were visited.
+	Fields int
+	// Defaulted is the number of those fields that fell back to a default.
+	Defaulted int
+	// Missing is the number of fields whose variable was unset and that had
+	// no default, whether or not that was an error.
+	Missing int
+	// Duration is the total time spent parsing.
+	Duration time.Duration
+}
+
+// countField tallies a visited field in the stats and report. It is safe for
+// concurrent use.
+func (p *Parser) countField(key string, defaulted, missing bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats.Fields++
+	switch {
+	case defaulted:
+		p.stats.Defaulted++
+		p.report.Defaulted = append(p.report.Defaulted, key)
+	case missing:
+		p.stats.Missing++
+		p.report.Missing = append(p.report.Missing, key)
+	default:
+		p.report.FromEnv = append(p.report.FromEnv, key)
+	}
+}
+
+// ParseWithStats parses target like Parse and also reports how many fields
+// were processed, defaulted and missing and how long the parse took. The
+// stats are returned even when parsing fails.
+func (p *Parser) ParseWithStats(target interface{}) (Stats, error) {
+	start := time.Now()
+	err := p.Parse(target)
+	stats := p.stats
+	stats.Duration = time.Since(start)
+	return stats, err
+}
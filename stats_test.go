@@ -0,0 +1,38 @@
+package envparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseWithStats(t *testing.T) {
+	t.Setenv("HOST", "localhost")
+	t.Setenv("TOKEN", "abc")
+	type Env struct {
+		Host     string `env:"HOST"`
+		Port     int    `env:"PORT" default:"8080"`
+		Debug    bool   `env:"DEBUG" default:"false"`
+		Token    string `env:"TOKEN" group:"auth"`
+		Password string `env:"PASSWORD" group:"auth"`
+		Ignored  string `env:"-"`
+	}
+	var env Env
+	stats, err := New().ParseWithStats(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, stats.Fields, 5)
+	assert.Equal(t, stats.Defaulted, 2)
+	assert.Equal(t, stats.Missing, 1)
+	assert.True(t, stats.Duration > 0)
+}
+
+func TestParser_ParseWithStats_Error(t *testing.T) {
+	type Env struct {
+		Name string `env:"NAME"`
+	}
+	var env Env
+	stats, err := New().ParseWithStats(&env)
+	assert.Error(t, err)
+	assert.Equal(t, stats.Fields, 1)
+	assert.Equal(t, stats.Missing, 1)
+}
@@ -0,0 +1,54 @@
+package envparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Time is a time.Time for use inside values decoded with encoding:"json".
+// Unlike time.Time it accepts both RFC3339 strings and epoch seconds.
+type Time time.Time
+
+// UnmarshalJSON accepts an RFC3339 string such as "2023-10-01T15:04:05Z" or
+// a number of seconds since the Unix epoch such as 1696172645.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		parsed, err := parseTime(s)
+		if err != nil {
+			return err
+		}
+		*t = Time(parsed)
+		return nil
+	}
+
+	var epoch json.Number
+	if err := json.Unmarshal(data, &epoch); err != nil {
+		return fmt.Errorf("time must be an RFC3339 string or epoch seconds: %v", err)
+	}
+	secs, err := epoch.Int64()
+	if err != nil {
+		return fmt.Errorf("time must be an RFC3339 string or epoch seconds: %v", err)
+	}
+	*t = Time(time.Unix(secs, 0).UTC())
+	return nil
+}
+
+// MarshalJSON encodes t as an RFC3339 string.
+func (t Time) MarshalJSON() ([]byte, error) {
+	return time.Time(t).MarshalJSON()
+}
+
+// Time returns t as a time.Time.
+func (t Time) Time() time.Time {
+	return time.Time(t)
+}
@@ -0,0 +1,35 @@
+package envparser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type timeEvent struct {
+	Start Time `json:"start"`
+	End   Time `json:"end"`
+}
+
+func TestParse_JSONTime(t *testing.T) {
+	t.Setenv("EVENT", `{"start":"2023-10-01T15:04:05Z","end":1696176245}`)
+	type Env struct {
+		Event timeEvent `env:"EVENT" encoding:"json"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.True(t, env.Event.Start.Time().Equal(time.Date(2023, 10, 1, 15, 4, 5, 0, time.UTC)))
+	assert.True(t, env.Event.End.Time().Equal(time.Date(2023, 10, 1, 16, 4, 5, 0, time.UTC)))
+}
+
+func TestParse_JSONTime_Error(t *testing.T) {
+	t.Setenv("EVENT", `{"start":true}`)
+	type Env struct {
+		Event timeEvent `env:"EVENT" encoding:"json"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
@@ -0,0 +1,10 @@
+//go:build go1.16
+// +build go1.16
+
+package envparser
+
+import "github.com/BurntSushi/toml"
+
+func init() {
+	RegisterCodec("toml", toml.Unmarshal)
+}
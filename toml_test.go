@@ -0,0 +1,42 @@
+//go:build go1.16
+// +build go1.16
+
+package envparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_Encoding_TOML(t *testing.T) {
+	t.Setenv("DB", `host = "db.internal"
+port = 5432
+replicas = ["a", "b"]`)
+	type Database struct {
+		Host     string   `toml:"host"`
+		Port     int      `toml:"port"`
+		Replicas []string `toml:"replicas"`
+	}
+	type Env struct {
+		DB Database `env:"DB" encoding:"toml"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.DB, Database{Host: "db.internal", Port: 5432, Replicas: []string{"a", "b"}})
+}
+
+func TestParse_Encoding_TOML_Error(t *testing.T) {
+	t.Setenv("DB", `host = "db.internal`)
+	type Database struct {
+		Host string `toml:"host"`
+	}
+	type Env struct {
+		DB Database `env:"DB" encoding:"toml"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "env 'DB'")
+}
@@ -0,0 +1,185 @@
+package envparser
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validate checks a parsed field against its min, max and oneof tags. For
+// slices the constraints apply to each element rather than the slice itself.
+func validate(field reflect.Value, tag reflect.StructTag) error {
+	if field.Kind() == reflect.Slice || field.Kind() == reflect.Map {
+		if err := validateLen(field, tag); err != nil {
+			return err
+		}
+	}
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < field.Len(); i++ {
+			if err := validateValue(field.Index(i), tag); err != nil {
+				return fmt.Errorf("element %d: %v", i, err)
+			}
+		}
+		if tag.Get("monotonic") == "true" {
+			return validateMonotonic(field)
+		}
+		return nil
+	}
+	return validateValue(field, tag)
+}
+
+func validateValue(v reflect.Value, tag reflect.StructTag) error {
+	if min, ok := tag.Lookup("min"); ok {
+		cmp, err := compareBound(v, min)
+		if err != nil {
+			return err
+		}
+		if cmp < 0 {
+			return fmt.Errorf("%v is less than minimum %s", v.Interface(), min)
+		}
+	}
+	if max, ok := tag.Lookup("max"); ok {
+		cmp, err := compareBound(v, max)
+		if err != nil {
+			return err
+		}
+		if cmp > 0 {
+			return fmt.Errorf("%v is greater than maximum %s", v.Interface(), max)
+		}
+	}
+	if oneof, ok := tag.Lookup("oneof"); ok {
+		s := fmt.Sprint(v.Interface())
+		for _, allowed := range strings.Split(oneof, ",") {
+			if s == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %s", s, oneof)
+	}
+	return nil
+}
+
+// validateLen checks the number of elements of a slice or map against its
+// minLen, maxLen and lenMultiple tags.
+func validateLen(field reflect.Value, tag reflect.StructTag) error {
+	if s, ok := tag.Lookup("minLen"); ok {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid minLen %q", s)
+		}
+		if field.Len() < n {
+			return fmt.Errorf("has %d elements, fewer than minimum %d", field.Len(), n)
+		}
+	}
+	if s, ok := tag.Lookup("maxLen"); ok {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid maxLen %q", s)
+		}
+		if field.Len() > n {
+			return fmt.Errorf("has %d elements, more than maximum %d", field.Len(), n)
+		}
+	}
+	if s, ok := tag.Lookup("lenMultiple"); ok {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid lenMultiple %q", s)
+		}
+		if field.Len()%n != 0 {
+			return fmt.Errorf("has %d elements, not a multiple of %d", field.Len(), n)
+		}
+	}
+	return nil
+}
+
+// validateMonotonic checks that every element of the slice is strictly
+// greater than the one before it.
+func validateMonotonic(field reflect.Value) error {
+	for i := 1; i < field.Len(); i++ {
+		prev, cur := field.Index(i-1), field.Index(i)
+		var increasing bool
+		switch cur.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			increasing = cur.Int() > prev.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			increasing = cur.Uint() > prev.Uint()
+		case reflect.Float32, reflect.Float64:
+			increasing = cur.Float() > prev.Float()
+		case reflect.String:
+			increasing = cur.String() > prev.String()
+		default:
+			return fmt.Errorf("monotonic not supported for %s", cur.Type())
+		}
+		if !increasing {
+			return fmt.Errorf("element %d (%v) is not greater than element %d (%v)", i, cur.Interface(), i-1, prev.Interface())
+		}
+	}
+	return nil
+}
+
+// compareBound compares v with the bound from a min or max tag, returning -1,
+// 0 or 1. Strings are compared by length and durations by duration syntax.
+func compareBound(v reflect.Value, bound string) (int, error) {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(bound)
+		if err != nil {
+			return 0, fmt.Errorf("invalid bound %q: %v", bound, err)
+		}
+		return compareInts(v.Int(), int64(d)), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b, err := strconv.ParseInt(bound, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid bound %q: %v", bound, err)
+		}
+		return compareInts(v.Int(), b), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		b, err := strconv.ParseUint(bound, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid bound %q: %v", bound, err)
+		}
+		switch {
+		case v.Uint() < b:
+			return -1, nil
+		case v.Uint() > b:
+			return 1, nil
+		}
+		return 0, nil
+
+	case reflect.Float32, reflect.Float64:
+		b, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid bound %q: %v", bound, err)
+		}
+		switch {
+		case v.Float() < b:
+			return -1, nil
+		case v.Float() > b:
+			return 1, nil
+		}
+		return 0, nil
+
+	case reflect.String:
+		b, err := strconv.Atoi(bound)
+		if err != nil {
+			return 0, fmt.Errorf("invalid bound %q: %v", bound, err)
+		}
+		return compareInts(int64(v.Len()), int64(b)), nil
+	}
+	return 0, fmt.Errorf("min/max not supported for %s", v.Type())
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
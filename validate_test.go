@@ -0,0 +1,151 @@
+package envparser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_MinMax(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	type Env struct {
+		Port int `env:"PORT" min:"1" max:"65535"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Port, 8080)
+}
+
+func TestParse_MinMax_Error(t *testing.T) {
+	t.Setenv("PORT", "70000")
+	type Env struct {
+		Port int `env:"PORT" min:"1" max:"65535"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_OneOf_Error(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "verbose")
+	type Env struct {
+		LogLevel string `env:"LOG_LEVEL" oneof:"debug,info,warn,error"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_SliceElementValidation(t *testing.T) {
+	t.Setenv("PORTS", "80,443,8080")
+	t.Setenv("LEVELS", "info,warn")
+	type Env struct {
+		Ports  []int    `env:"PORTS" min:"1" max:"65535"`
+		Levels []string `env:"LEVELS" oneof:"debug,info,warn,error"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Ports, []int{80, 443, 8080})
+	assert.Equal(t, env.Levels, []string{"info", "warn"})
+}
+
+func TestParse_SliceElementValidation_Error(t *testing.T) {
+	t.Setenv("PORTS", "80,0,8080")
+	type Env struct {
+		Ports []int `env:"PORTS" min:"1" max:"65535"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "element 1")
+}
+
+func TestParse_DurationSliceValidation(t *testing.T) {
+	t.Setenv("BACKOFF", "100ms,1s,10s")
+	type Env struct {
+		Backoff []time.Duration `env:"BACKOFF" min:"10ms" max:"1m" monotonic:"true"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Backoff, []time.Duration{100 * time.Millisecond, time.Second, 10 * time.Second})
+}
+
+func TestParse_DurationSliceValidation_OutOfRange_Error(t *testing.T) {
+	t.Setenv("BACKOFF", "100ms,1s,2m")
+	type Env struct {
+		Backoff []time.Duration `env:"BACKOFF" min:"10ms" max:"1m"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_DurationSliceValidation_NotMonotonic_Error(t *testing.T) {
+	t.Setenv("BACKOFF", "100ms,10s,1s")
+	type Env struct {
+		Backoff []time.Duration `env:"BACKOFF" monotonic:"true"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "element 2")
+}
+
+func TestParse_SliceLength(t *testing.T) {
+	t.Setenv("HOSTS", "a,b")
+	t.Setenv("LABELS", "a=1")
+	type Env struct {
+		Hosts  []string          `env:"HOSTS" minLen:"1" maxLen:"3"`
+		Labels map[string]string `env:"LABELS" minLen:"1" maxLen:"3"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Hosts, []string{"a", "b"})
+}
+
+func TestParse_SliceLength_TooFew_Error(t *testing.T) {
+	t.Setenv("HOSTS", "")
+	type Env struct {
+		Hosts []string `env:"HOSTS" minLen:"1" maxLen:"3"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_SliceLength_TooMany_Error(t *testing.T) {
+	t.Setenv("LABELS", "a=1,b=2,c=3,d=4")
+	type Env struct {
+		Labels map[string]string `env:"LABELS" minLen:"1" maxLen:"3"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+}
+
+func TestParse_LenMultiple(t *testing.T) {
+	t.Setenv("PAIRS", "a,1,b,2")
+	type Env struct {
+		Pairs []string `env:"PAIRS" lenMultiple:"2"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.Pairs, []string{"a", "1", "b", "2"})
+}
+
+func TestParse_LenMultiple_Error(t *testing.T) {
+	t.Setenv("PAIRS", "a,1,b")
+	type Env struct {
+		Pairs []string `env:"PAIRS" lenMultiple:"2"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has 3 elements, not a multiple of 2")
+}
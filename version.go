@@ -0,0 +1,150 @@
+package envparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version such as 1.2.3, 1.2.3-rc.1 or 1.2.3+build.5.
+// It implements encoding.TextUnmarshaler, so Version and []Version fields are
+// parsed from variables like MIN_VERSION=1.2.3 without extra tags.
+type Version struct {
+	Major, Minor, Patch int
+	// PreRelease holds the dot-separated identifiers after '-', e.g. "rc.1".
+	PreRelease string
+	// Build holds the metadata after '+'. It is ignored by Compare.
+	Build string
+}
+
+// UnmarshalText parses a version of the form MAJOR.MINOR.PATCH with optional
+// -PRERELEASE and +BUILD suffixes. A leading 'v' is accepted.
+func (v *Version) UnmarshalText(text []byte) error {
+	s := strings.TrimPrefix(string(text), "v")
+	var parsed Version
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		parsed.Build = s[i+1:]
+		s = s[:i]
+		if err := checkIdentifiers(parsed.Build, false); err != nil {
+			return fmt.Errorf("invalid version %q: build %v", text, err)
+		}
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		parsed.PreRelease = s[i+1:]
+		s = s[:i]
+		if err := checkIdentifiers(parsed.PreRelease, true); err != nil {
+			return fmt.Errorf("invalid version %q: pre-release %v", text, err)
+		}
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid version %q: want MAJOR.MINOR.PATCH", text)
+	}
+	nums := [3]*int{&parsed.Major, &parsed.Minor, &parsed.Patch}
+	for i, part := range parts {
+		if part == "" || !isDigits(part) || (len(part) > 1 && part[0] == '0') {
+			return fmt.Errorf("invalid version %q: %q is not a number", text, part)
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %v", text, err)
+		}
+		*nums[i] = n
+	}
+	*v = parsed
+	return nil
+}
+
+// String formats v as MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD].
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease != "" {
+		s += "-" + v.PreRelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 as v is lower than, equal to or higher than
+// other, following semantic versioning precedence: a pre-release sorts before
+// its release and build metadata is ignored.
+func (v Version) Compare(other Version) int {
+	for _, d := range [3]int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	switch {
+	case v.PreRelease == other.PreRelease:
+		return 0
+	case v.PreRelease == "":
+		return 1
+	case other.PreRelease == "":
+		return -1
+	}
+
+	a, b := strings.Split(v.PreRelease, "."), strings.Split(other.PreRelease, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareIdentifier(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// Less reports whether v has lower precedence than other.
+func (v Version) Less(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+// compareIdentifier orders two pre-release identifiers: numeric ones compare
+// numerically and sort before alphanumeric ones, which compare as strings.
+func compareIdentifier(a, b string) int {
+	an, bn := isDigits(a), isDigits(b)
+	switch {
+	case an && bn:
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+	case an:
+		return -1
+	case bn:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// checkIdentifiers validates dot-separated identifiers made of ASCII letters,
+// digits and hyphens. Numeric pre-release identifiers may not have leading
+// zeros.
+func checkIdentifiers(s string, preRelease bool) error {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return fmt.Errorf("has an empty identifier")
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return fmt.Errorf("identifier %q has invalid character %q", id, r)
+			}
+		}
+		if preRelease && isDigits(id) && len(id) > 1 && id[0] == '0' {
+			return fmt.Errorf("identifier %q has a leading zero", id)
+		}
+	}
+	return nil
+}
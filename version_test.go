@@ -0,0 +1,64 @@
+package envparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_Version(t *testing.T) {
+	t.Setenv("MIN_VERSION", "1.2.3")
+	t.Setenv("NEXT_VERSION", "v1.2.3-rc1+build.5")
+	t.Setenv("SUPPORTED", "1.0.0, 2.1.0-beta.2")
+	type Env struct {
+		MinVersion  Version   `env:"MIN_VERSION"`
+		NextVersion Version   `env:"NEXT_VERSION"`
+		Supported   []Version `env:"SUPPORTED"`
+	}
+	var env Env
+	err := Parse(&env)
+	assert.NoError(t, err)
+	assert.Equal(t, env.MinVersion, Version{Major: 1, Minor: 2, Patch: 3})
+	assert.Equal(t, env.NextVersion, Version{Major: 1, Minor: 2, Patch: 3, PreRelease: "rc1", Build: "build.5"})
+	assert.Equal(t, env.Supported, []Version{{Major: 1}, {Major: 2, Minor: 1, PreRelease: "beta.2"}})
+	assert.Equal(t, env.NextVersion.String(), "1.2.3-rc1+build.5")
+	assert.True(t, env.NextVersion.Less(env.MinVersion))
+}
+
+func TestParse_Version_Error(t *testing.T) {
+	for _, val := range []string{"1.2", "1.2.x", "01.2.3", "1.2.3-", "1.2.3-rc..1", "1.2.3+b_1", "latest"} {
+		t.Run(val, func(t *testing.T) {
+			t.Setenv("MIN_VERSION", val)
+			type Env struct {
+				MinVersion Version `env:"MIN_VERSION"`
+			}
+			var env Env
+			err := Parse(&env)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.10.0", "2.0.0"}
+	versions := make([]Version, len(ordered))
+	for i, s := range ordered {
+		assert.NoError(t, versions[i].UnmarshalText([]byte(s)))
+	}
+	for i := range versions {
+		for j := range versions {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			assert.Equal(t, versions[i].Compare(versions[j]), want, "%s vs %s", ordered[i], ordered[j])
+		}
+	}
+
+	var a, b Version
+	assert.NoError(t, a.UnmarshalText([]byte("1.0.0+linux")))
+	assert.NoError(t, b.UnmarshalText([]byte("1.0.0+darwin")))
+	assert.Equal(t, a.Compare(b), 0)
+}